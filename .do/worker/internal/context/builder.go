@@ -3,15 +3,28 @@ package context
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/do-focus/worker/internal/db"
 	"github.com/do-focus/worker/pkg/models"
 )
 
+// TerminalSource supplies the last lines of the caller's terminal, so
+// BuildContext can fold "what the user was just staring at" into the
+// assembled context without requiring a manual capture + paste step. The
+// do-focus CLI's terminal capture backends are the expected implementation;
+// this interface exists so the context package doesn't need to depend on
+// the CLI module to use them.
+type TerminalSource interface {
+	// CaptureTail returns the last lines lines of the current terminal.
+	CaptureTail(lines int) (string, error)
+}
+
 // Builder constructs context for session injection.
 type Builder struct {
 	db       db.Adapter
 	renderer *Renderer
+	terminal TerminalSource
 }
 
 // NewBuilder creates a new context builder.
@@ -22,31 +35,52 @@ func NewBuilder(adapter db.Adapter) *Builder {
 	}
 }
 
-// BuildContext assembles the full context for a user.
-func (b *Builder) BuildContext(ctx context.Context, userName string, opts BuildOptions) (*models.ContextInjectResponse, error) {
+// WithTerminalSource sets the TerminalSource BuildContext uses when
+// BuildOptions.IncludeTerminalCapture is set. Without one, that option is
+// silently ignored since there's nothing to capture from.
+func (b *Builder) WithTerminalSource(src TerminalSource) *Builder {
+	b.terminal = src
+	return b
+}
+
+// Use registers a middleware to run against the assembled context before
+// rendering, regardless of which output format is selected. See Renderer.Use.
+func (b *Builder) Use(mw Middleware) *Builder {
+	b.renderer.Use(mw)
+	return b
+}
+
+// BuildContext assembles the full context for scope's user. scope also
+// carries the authorization the underlying Adapter methods enforce (every
+// Adapter query takes a db.ScopeContext, not a bare username) so results
+// never cross a scope boundary.
+func (b *Builder) BuildContext(ctx context.Context, scope db.ScopeContext, opts BuildOptions) (*models.ContextInjectResponse, error) {
 	resp := &models.ContextInjectResponse{}
 
 	// Get latest session
-	session, err := b.db.GetLatestSession(ctx, userName)
+	session, err := b.db.GetLatestSession(ctx, scope)
 	if err != nil {
 		return nil, err
 	}
 	resp.Session = session
 
-	// Get recent observations
-	limit := opts.ObservationLimit
-	if limit <= 0 {
-		limit = 20
-	}
-	observations, err := b.db.GetRecentObservations(ctx, userName, limit)
-	if err != nil {
-		return nil, err
+	// Get recent observations, unless the caller explicitly asked for none
+	// via a negative ObservationLimit (used for a "session only" build).
+	if opts.ObservationLimit >= 0 {
+		limit := opts.ObservationLimit
+		if limit == 0 {
+			limit = 20
+		}
+		observations, err := b.db.GetRecentObservations(ctx, scope, limit)
+		if err != nil {
+			return nil, err
+		}
+		resp.Observations = observations
 	}
-	resp.Observations = observations
 
 	// Get active plan
 	if opts.IncludePlan {
-		plan, err := b.db.GetActivePlan(ctx, userName)
+		plan, err := b.db.GetActivePlan(ctx, scope)
 		if err != nil {
 			return nil, err
 		}
@@ -55,25 +89,78 @@ func (b *Builder) BuildContext(ctx context.Context, userName string, opts BuildO
 
 	// Get team context
 	if opts.IncludeTeam {
-		team, err := b.db.GetTeamContext(ctx, userName)
+		team, err := b.db.GetTeamContext(ctx, scope)
 		if err != nil {
 			return nil, err
 		}
 		resp.TeamContext = team
 	}
 
-	// Render markdown
-	resp.Markdown = b.renderer.RenderContext(resp)
+	// Capture the caller's terminal tail, if a TerminalSource is wired up.
+	// A capture failure here isn't fatal to context building: the terminal
+	// may simply not be capturable (headless caller, unsupported terminal,
+	// etc.), so we fall back to omitting TerminalTail rather than failing
+	// the whole request.
+	if opts.IncludeTerminalCapture && b.terminal != nil {
+		captureLines := opts.TerminalCaptureLines
+		if captureLines <= 0 {
+			captureLines = 200
+		}
+		if tail, err := b.terminal.CaptureTail(captureLines); err == nil {
+			resp.TerminalTail = tail
+		}
+	}
+
+	// Run hooks (redaction, dedup, team filtering, ...) registered via
+	// b.renderer.Use before anything is rendered.
+	if err := b.renderer.applyMiddlewares(resp); err != nil {
+		return nil, fmt.Errorf("apply context middleware: %w", err)
+	}
+
+	// Render output in the requested format, defaulting to markdown for
+	// backward compatibility with callers that only read resp.Markdown.
+	format := opts.Format
+	if format == "" {
+		format = "markdown"
+	}
+	renderer, err := GetRenderer(format)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := renderer.Render(resp)
+	if err != nil {
+		return nil, fmt.Errorf("render context as %s: %w", format, err)
+	}
+	if format == "markdown" {
+		resp.Markdown = string(rendered)
+	} else {
+		resp.Markdown = b.renderer.RenderContext(resp)
+	}
+	resp.Rendered = rendered
+	resp.Format = format
 
 	return resp, nil
 }
 
 // BuildOptions configures context building.
 type BuildOptions struct {
+	// ObservationLimit caps how many observations are fetched. 0 falls
+	// back to 20; a negative value skips fetching observations entirely
+	// (resp.Observations stays nil), for a "session only" build.
 	ObservationLimit int
 	IncludePlan      bool
 	IncludeTeam      bool
 	IncludeSession   bool
+	// Format selects the output renderer (markdown, json, yaml, html, text).
+	// Defaults to "markdown" when empty.
+	Format string
+	// IncludeTerminalCapture fills resp.TerminalTail from the Builder's
+	// TerminalSource (set via WithTerminalSource). Has no effect if no
+	// TerminalSource was configured.
+	IncludeTerminalCapture bool
+	// TerminalCaptureLines bounds how many lines of terminal tail to
+	// request when IncludeTerminalCapture is set. Defaults to 200.
+	TerminalCaptureLines int
 }
 
 // DefaultBuildOptions returns the default build options.
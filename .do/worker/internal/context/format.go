@@ -0,0 +1,64 @@
+package context
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+// ContextRenderer converts context data into a byte representation for a
+// specific output format (markdown, JSON, YAML, HTML, plain text, ...).
+type ContextRenderer interface {
+	// Name returns the registry key for this renderer (e.g. "markdown", "json").
+	Name() string
+	// Render converts the context into its output representation.
+	Render(ctx *models.ContextInjectResponse) ([]byte, error)
+}
+
+// registry holds the globally registered renderers, keyed by format name.
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]ContextRenderer{}
+)
+
+// RegisterRenderer adds a renderer to the global registry under its Name().
+// Registering a renderer under a name that already exists overwrites the
+// previous one, so callers can replace a built-in renderer with a custom one.
+func RegisterRenderer(r ContextRenderer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[r.Name()] = r
+}
+
+// GetRenderer looks up a renderer by format name.
+func GetRenderer(format string) (ContextRenderer, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	r, ok := registry[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown renderer format: %q", format)
+	}
+	return r, nil
+}
+
+// Formats returns the names of all currently registered renderers.
+func Formats() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	return names
+}
+
+func init() {
+	RegisterRenderer(NewRenderer())
+	RegisterRenderer(NewJSONRenderer())
+	RegisterRenderer(NewYAMLRenderer())
+	RegisterRenderer(NewHTMLRenderer())
+	RegisterRenderer(NewTextRenderer())
+}
@@ -0,0 +1,29 @@
+package context
+
+import (
+	"github.com/do-focus/worker/pkg/models"
+)
+
+// Middleware transforms the assembled context before it is rendered. This
+// is the place for policy (redaction, deduplication, team filtering) that
+// should apply regardless of which ContextRenderer eventually formats the
+// output, so it stays out of each renderer implementation.
+type Middleware func(*models.ContextInjectResponse) error
+
+// Use registers a middleware to run, in order, before rendering. Returns
+// the receiver so calls can be chained.
+func (r *Renderer) Use(mw Middleware) *Renderer {
+	r.middlewares = append(r.middlewares, mw)
+	return r
+}
+
+// applyMiddlewares runs all registered middlewares against ctx, stopping at
+// the first error.
+func (r *Renderer) applyMiddlewares(ctx *models.ContextInjectResponse) error {
+	for _, mw := range r.middlewares {
+		if err := mw(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
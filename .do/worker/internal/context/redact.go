@@ -0,0 +1,127 @@
+package context
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+// redactionPattern pairs a detector regex with the replacement text used
+// when it matches.
+type redactionPattern struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// defaultRedactionPatterns covers the credential shapes most likely to leak
+// into observation/summary/plan content before it's piped to a third-party
+// LLM frontend.
+var defaultRedactionPatterns = []redactionPattern{
+	{regexp.MustCompile(`sk-ant-[A-Za-z0-9_-]{20,}`), "[REDACTED_ANTHROPIC_KEY]"},
+	{regexp.MustCompile(`sk-[A-Za-z0-9]{20,}`), "[REDACTED_API_KEY]"},
+	{regexp.MustCompile(`AKIA[0-9A-Z]{16}`), "[REDACTED_AWS_KEY]"},
+	{regexp.MustCompile(`(?i)aws_secret_access_key\s*[:=]\s*\S+`), "aws_secret_access_key=[REDACTED]"},
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), "[REDACTED_JWT]"},
+	{regexp.MustCompile(`ghp_[A-Za-z0-9]{36}`), "[REDACTED_GITHUB_TOKEN]"},
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[REDACTED_EMAIL]"},
+}
+
+func redactString(s string, patterns []redactionPattern) string {
+	for _, p := range patterns {
+		s = p.re.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// Redactor scrubs secrets and PII from context content before it is
+// rendered. Ship with sensible defaults and extend with AddPattern.
+type Redactor struct {
+	patterns []redactionPattern
+}
+
+// NewRedactor creates a Redactor preloaded with the default secret/PII
+// patterns (API keys, AWS credentials, JWTs, GitHub tokens, emails).
+func NewRedactor() *Redactor {
+	patterns := make([]redactionPattern, len(defaultRedactionPatterns))
+	copy(patterns, defaultRedactionPatterns)
+	return &Redactor{patterns: patterns}
+}
+
+// AddPattern registers an additional regex/replacement pair, run after the
+// built-in patterns.
+func (r *Redactor) AddPattern(re *regexp.Regexp, replacement string) *Redactor {
+	r.patterns = append(r.patterns, redactionPattern{re: re, replacement: replacement})
+	return r
+}
+
+// Middleware returns this Redactor as a context Middleware.
+func (r *Redactor) Middleware() Middleware {
+	return func(ctx *models.ContextInjectResponse) error {
+		if ctx.Session != nil {
+			ctx.Session.Summary = redactString(ctx.Session.Summary, r.patterns)
+		}
+		if ctx.ActivePlan != nil {
+			ctx.ActivePlan.Content = redactString(ctx.ActivePlan.Content, r.patterns)
+		}
+		for i := range ctx.Observations {
+			ctx.Observations[i].Content = redactString(ctx.Observations[i].Content, r.patterns)
+		}
+		for i := range ctx.TeamContext {
+			ctx.TeamContext[i].Summary = redactString(ctx.TeamContext[i].Summary, r.patterns)
+		}
+		return nil
+	}
+}
+
+// DedupeObservations returns a Middleware that drops observations whose
+// content hash has already been seen, keeping the first (most important,
+// since observations arrive pre-sorted by importance/recency).
+func DedupeObservations() Middleware {
+	return func(ctx *models.ContextInjectResponse) error {
+		seen := make(map[string]bool, len(ctx.Observations))
+		deduped := ctx.Observations[:0]
+		for _, obs := range ctx.Observations {
+			sum := sha256.Sum256([]byte(obs.Content))
+			key := hex.EncodeToString(sum[:])
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			deduped = append(deduped, obs)
+		}
+		ctx.Observations = deduped
+		return nil
+	}
+}
+
+// FilterTeamMembers returns a Middleware that restricts team context to an
+// allow-list (when non-empty) and always excludes any deny-listed user.
+func FilterTeamMembers(allow, deny []string) Middleware {
+	allowSet := toSet(allow)
+	denySet := toSet(deny)
+
+	return func(ctx *models.ContextInjectResponse) error {
+		filtered := ctx.TeamContext[:0]
+		for _, t := range ctx.TeamContext {
+			if denySet[t.UserName] {
+				continue
+			}
+			if len(allowSet) > 0 && !allowSet[t.UserName] {
+				continue
+			}
+			filtered = append(filtered, t)
+		}
+		ctx.TeamContext = filtered
+		return nil
+	}
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set
+}
@@ -12,6 +12,14 @@ import (
 type Renderer struct {
 	maxObservations int
 	maxTeamMembers  int
+
+	// scoring, when non-nil, enables score-based observation ranking
+	// instead of the legacy "importance >= 4" / fixed per-type cutoff.
+	scoring  *ScoringConfig
+	halfLife time.Duration
+
+	// middlewares run against the context before rendering; see Use.
+	middlewares []Middleware
 }
 
 // NewRenderer creates a new renderer with default settings.
@@ -22,6 +30,31 @@ func NewRenderer() *Renderer {
 	}
 }
 
+// WithScoring enables score-based ranking of observations using the given
+// weights, selecting top-K globally and top-K per type via a min-heap
+// instead of the fixed "importance >= 4" / "5 per type" behavior.
+func (r *Renderer) WithScoring(weights ScoringConfig) *Renderer {
+	r.scoring = &weights
+	return r
+}
+
+// WithHalfLife sets the recency decay half-life used by score-based
+// ranking. Has no effect unless WithScoring has also been called.
+func (r *Renderer) WithHalfLife(d time.Duration) *Renderer {
+	r.halfLife = d
+	return r
+}
+
+// Name returns the registry key for this renderer.
+func (r *Renderer) Name() string {
+	return "markdown"
+}
+
+// Render implements ContextRenderer.
+func (r *Renderer) Render(ctx *models.ContextInjectResponse) ([]byte, error) {
+	return []byte(r.RenderContext(ctx)), nil
+}
+
 // RenderContext generates markdown from context data.
 func (r *Renderer) RenderContext(ctx *models.ContextInjectResponse) string {
 	var sb strings.Builder
@@ -48,6 +81,11 @@ func (r *Renderer) RenderContext(ctx *models.ContextInjectResponse) string {
 		r.renderTeamContext(&sb, ctx.TeamContext)
 	}
 
+	// Render the captured terminal tail, if any.
+	if ctx.TerminalTail != "" {
+		sb.WriteString(r.RenderANSIBlock("Terminal", ctx.TerminalTail))
+	}
+
 	return sb.String()
 }
 
@@ -87,10 +125,20 @@ func (r *Renderer) renderPlan(sb *strings.Builder, plan *models.Plan) {
 	sb.WriteString("\n\n")
 }
 
-// renderObservations renders recent observations.
+// renderObservations renders recent observations. When scoring is
+// configured via WithScoring, observations are ranked by
+// score = w_imp*Importance + w_rec*exp(-Δt/halflife) + w_type*typeWeight[type]
+// and the top-K globally (and top-K per type) are selected with a min-heap.
+// Otherwise it falls back to the legacy "importance >= 4" / "5 per type"
+// behavior.
 func (r *Renderer) renderObservations(sb *strings.Builder, observations []models.Observation) {
 	sb.WriteString("## Recent Observations\n\n")
 
+	if r.scoring != nil {
+		r.renderScoredObservations(sb, observations)
+		return
+	}
+
 	// Group by type
 	byType := make(map[string][]models.Observation)
 	for _, obs := range observations {
@@ -128,6 +176,49 @@ func (r *Renderer) renderObservations(sb *strings.Builder, observations []models
 	}
 }
 
+// renderScoredObservations selects and renders observations using the
+// configured ScoringConfig.
+func (r *Renderer) renderScoredObservations(sb *strings.Builder, observations []models.Observation) {
+	now := time.Now()
+	cfg := *r.scoring
+
+	scored := make([]scoredObservation, len(observations))
+	for i, obs := range observations {
+		scored[i] = scoredObservation{obs: obs, score: score(obs, now, cfg, r.halfLife)}
+	}
+
+	top := topK(scored, cfg.TopK)
+	if len(top) > 0 {
+		sb.WriteString("### Top Ranked\n\n")
+		for _, obs := range top {
+			r.renderObservation(sb, obs)
+		}
+		sb.WriteString("\n")
+	}
+
+	if cfg.TopKPerType <= 0 {
+		return
+	}
+
+	byType := make(map[string][]scoredObservation)
+	for _, s := range scored {
+		byType[s.obs.Type] = append(byType[s.obs.Type], s)
+	}
+
+	typeOrder := []string{"decision", "insight", "learning", "pattern"}
+	for _, t := range typeOrder {
+		group, ok := byType[t]
+		if !ok {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("### %s\n\n", capitalize(t)))
+		for _, obs := range topK(group, cfg.TopKPerType) {
+			r.renderObservation(sb, obs)
+		}
+		sb.WriteString("\n")
+	}
+}
+
 // renderObservation renders a single observation.
 func (r *Renderer) renderObservation(sb *strings.Builder, obs models.Observation) {
 	importance := ""
@@ -143,6 +234,28 @@ func (r *Renderer) renderObservation(sb *strings.Builder, obs models.Observation
 	sb.WriteString(fmt.Sprintf("- %s%s%s\n", obs.Content, importance, agent))
 }
 
+// RenderANSIBlock renders a colorized terminal snippet as a fenced ```ansi
+// code block, with raw preserved as-is (including its SGR escape codes)
+// so downstream Markdown viewers that understand ```ansi can re-colorize
+// it. title is rendered as a heading above the block; pass "" to omit it.
+//
+// This doesn't use the do-focus CLI's internal/capture/ansi tokenizer:
+// that package lives in the root do-focus module, which this worker
+// module has no dependency on. raw is written out unparsed.
+func (r *Renderer) RenderANSIBlock(title, raw string) string {
+	var sb strings.Builder
+	if title != "" {
+		sb.WriteString(fmt.Sprintf("### %s\n\n", title))
+	}
+	sb.WriteString("```ansi\n")
+	sb.WriteString(raw)
+	if !strings.HasSuffix(raw, "\n") {
+		sb.WriteString("\n")
+	}
+	sb.WriteString("```\n\n")
+	return sb.String()
+}
+
 // renderTeamContext renders team member activity.
 func (r *Renderer) renderTeamContext(sb *strings.Builder, team []models.TeamContext) {
 	sb.WriteString("## Team Activity\n\n")
@@ -0,0 +1,88 @@
+package context
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+// HTMLRenderer renders context data as a standalone HTML fragment, suitable
+// for embedding in a dashboard or viewing directly in a browser.
+type HTMLRenderer struct{}
+
+// NewHTMLRenderer creates a new HTML renderer.
+func NewHTMLRenderer() *HTMLRenderer {
+	return &HTMLRenderer{}
+}
+
+// Name returns the registry key for this renderer.
+func (r *HTMLRenderer) Name() string {
+	return "html"
+}
+
+// Render implements ContextRenderer.
+func (r *HTMLRenderer) Render(ctx *models.ContextInjectResponse) ([]byte, error) {
+	var sb strings.Builder
+
+	sb.WriteString("<section class=\"do-context\">\n")
+	sb.WriteString("<h1>Do Memory Context</h1>\n")
+
+	if ctx.Session != nil {
+		s := ctx.Session
+		sb.WriteString("<h2>Current Session</h2>\n<ul>\n")
+		fmt.Fprintf(&sb, "<li><strong>ID</strong>: <code>%s</code></li>\n", html.EscapeString(s.ID))
+		fmt.Fprintf(&sb, "<li><strong>User</strong>: %s</li>\n", html.EscapeString(s.UserName))
+		fmt.Fprintf(&sb, "<li><strong>Started</strong>: %s</li>\n", s.StartedAt.Format(time.RFC3339))
+		if s.EndedAt != nil {
+			fmt.Fprintf(&sb, "<li><strong>Ended</strong>: %s</li>\n", s.EndedAt.Format(time.RFC3339))
+		}
+		sb.WriteString("</ul>\n")
+		if s.Summary != "" {
+			fmt.Fprintf(&sb, "<p><strong>Summary</strong>: %s</p>\n", html.EscapeString(s.Summary))
+		}
+	}
+
+	if ctx.ActivePlan != nil {
+		p := ctx.ActivePlan
+		sb.WriteString("<h2>Active Plan</h2>\n")
+		fmt.Fprintf(&sb, "<h3>%s</h3>\n", html.EscapeString(p.Title))
+		fmt.Fprintf(&sb, "<p><strong>Status</strong>: %s</p>\n", html.EscapeString(p.Status))
+		fmt.Fprintf(&sb, "<pre>%s</pre>\n", html.EscapeString(p.Content))
+	}
+
+	if len(ctx.Observations) > 0 {
+		sb.WriteString("<h2>Recent Observations</h2>\n<ul>\n")
+		for _, obs := range ctx.Observations {
+			importance := ""
+			if obs.Importance >= 4 {
+				importance = " <strong>[!]</strong>"
+			}
+			agent := ""
+			if obs.AgentName != "" {
+				agent = fmt.Sprintf(" <em>(by %s)</em>", html.EscapeString(obs.AgentName))
+			}
+			fmt.Fprintf(&sb, "<li>%s%s%s</li>\n", html.EscapeString(obs.Content), importance, agent)
+		}
+		sb.WriteString("</ul>\n")
+	}
+
+	if len(ctx.TeamContext) > 0 {
+		sb.WriteString("<h2>Team Activity</h2>\n<ul>\n")
+		for _, t := range ctx.TeamContext {
+			fmt.Fprintf(&sb, "<li><strong>%s</strong>: %s</li>\n", html.EscapeString(t.UserName), html.EscapeString(t.Summary))
+		}
+		sb.WriteString("</ul>\n")
+	}
+
+	if ctx.TerminalTail != "" {
+		sb.WriteString("<h2>Terminal</h2>\n")
+		fmt.Fprintf(&sb, "<pre>%s</pre>\n", html.EscapeString(ctx.TerminalTail))
+	}
+
+	sb.WriteString("</section>\n")
+
+	return []byte(sb.String()), nil
+}
@@ -0,0 +1,25 @@
+package context
+
+import (
+	"encoding/json"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+// JSONRenderer renders context data as indented JSON.
+type JSONRenderer struct{}
+
+// NewJSONRenderer creates a new JSON renderer.
+func NewJSONRenderer() *JSONRenderer {
+	return &JSONRenderer{}
+}
+
+// Name returns the registry key for this renderer.
+func (r *JSONRenderer) Name() string {
+	return "json"
+}
+
+// Render implements ContextRenderer.
+func (r *JSONRenderer) Render(ctx *models.ContextInjectResponse) ([]byte, error) {
+	return json.MarshalIndent(ctx, "", "  ")
+}
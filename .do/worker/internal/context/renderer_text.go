@@ -0,0 +1,68 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+// TextRenderer renders a compact plain-text form of the context, intended
+// for piping into tools that don't parse Markdown well (log shippers, diff
+// viewers, non-LLM consumers).
+type TextRenderer struct{}
+
+// NewTextRenderer creates a new plain-text renderer.
+func NewTextRenderer() *TextRenderer {
+	return &TextRenderer{}
+}
+
+// Name returns the registry key for this renderer.
+func (r *TextRenderer) Name() string {
+	return "text"
+}
+
+// Render implements ContextRenderer.
+func (r *TextRenderer) Render(ctx *models.ContextInjectResponse) ([]byte, error) {
+	var sb strings.Builder
+
+	if ctx.Session != nil {
+		s := ctx.Session
+		fmt.Fprintf(&sb, "session %s user=%s started=%s", s.ID, s.UserName, s.StartedAt.Format(time.RFC3339))
+		if s.EndedAt != nil {
+			fmt.Fprintf(&sb, " ended=%s", s.EndedAt.Format(time.RFC3339))
+		}
+		sb.WriteString("\n")
+		if s.Summary != "" {
+			fmt.Fprintf(&sb, "summary: %s\n", s.Summary)
+		}
+	}
+
+	if ctx.ActivePlan != nil {
+		p := ctx.ActivePlan
+		fmt.Fprintf(&sb, "plan [%s] %s\n", p.Status, p.Title)
+	}
+
+	for _, obs := range ctx.Observations {
+		marker := " "
+		if obs.Importance >= 4 {
+			marker = "!"
+		}
+		fmt.Fprintf(&sb, "%s %-10s %s", marker, obs.Type, obs.Content)
+		if obs.AgentName != "" {
+			fmt.Fprintf(&sb, " (%s)", obs.AgentName)
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, t := range ctx.TeamContext {
+		fmt.Fprintf(&sb, "team %-15s %s\n", t.UserName, t.Summary)
+	}
+
+	if ctx.TerminalTail != "" {
+		fmt.Fprintf(&sb, "terminal:\n%s\n", ctx.TerminalTail)
+	}
+
+	return []byte(sb.String()), nil
+}
@@ -0,0 +1,24 @@
+package context
+
+import (
+	"github.com/do-focus/worker/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLRenderer renders context data as YAML.
+type YAMLRenderer struct{}
+
+// NewYAMLRenderer creates a new YAML renderer.
+func NewYAMLRenderer() *YAMLRenderer {
+	return &YAMLRenderer{}
+}
+
+// Name returns the registry key for this renderer.
+func (r *YAMLRenderer) Name() string {
+	return "yaml"
+}
+
+// Render implements ContextRenderer.
+func (r *YAMLRenderer) Render(ctx *models.ContextInjectResponse) ([]byte, error) {
+	return yaml.Marshal(ctx)
+}
@@ -0,0 +1,105 @@
+package context
+
+import (
+	"container/heap"
+	"math"
+	"time"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+// ScoringConfig controls how renderObservations ranks observations when
+// scoring is enabled via Renderer.WithScoring. The final score is:
+//
+//	score = ImportanceWeight*Importance + RecencyWeight*exp(-Δt/halflife) + TypeWeight*TypeWeights[obs.Type]
+type ScoringConfig struct {
+	ImportanceWeight float64
+	RecencyWeight    float64
+	TypeWeight       float64
+	TypeWeights      map[string]float64
+
+	// TopK caps the number of observations rendered overall. 0 means
+	// unbounded.
+	TopK int
+	// TopKPerType caps the number of observations rendered per type. 0
+	// means unbounded.
+	TopKPerType int
+}
+
+// DefaultScoringConfig returns reasonable defaults roughly matching the
+// legacy "importance >= 4" / "5 per type" behavior.
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		ImportanceWeight: 1.0,
+		RecencyWeight:    2.0,
+		TypeWeight:       0.5,
+		TypeWeights: map[string]float64{
+			"decision": 1.0,
+			"insight":  0.8,
+			"learning": 0.6,
+			"pattern":  0.6,
+		},
+		TopK:        20,
+		TopKPerType: 5,
+	}
+}
+
+// score computes the weighted score of a single observation relative to now.
+func score(obs models.Observation, now time.Time, cfg ScoringConfig, halfLife time.Duration) float64 {
+	recency := 0.0
+	if halfLife > 0 {
+		dt := now.Sub(obs.CreatedAt)
+		if dt < 0 {
+			dt = 0
+		}
+		recency = math.Exp(-dt.Hours() / halfLife.Hours())
+	}
+	return cfg.ImportanceWeight*float64(obs.Importance) +
+		cfg.RecencyWeight*recency +
+		cfg.TypeWeight*cfg.TypeWeights[obs.Type]
+}
+
+// scoredObservation pairs an observation with its computed score.
+type scoredObservation struct {
+	obs   models.Observation
+	score float64
+}
+
+// scoredMinHeap is a min-heap of scoredObservation, used to maintain the
+// top-K highest-scoring observations while streaming through a larger set.
+type scoredMinHeap []scoredObservation
+
+func (h scoredMinHeap) Len() int            { return len(h) }
+func (h scoredMinHeap) Less(i, j int) bool  { return h[i].score < h[j].score }
+func (h scoredMinHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *scoredMinHeap) Push(x interface{}) { *h = append(*h, x.(scoredObservation)) }
+func (h *scoredMinHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// topK returns the k highest-scoring observations from scored, in
+// descending score order. If k <= 0, all observations are returned sorted.
+func topK(scored []scoredObservation, k int) []models.Observation {
+	if k <= 0 || k >= len(scored) {
+		k = len(scored)
+	}
+
+	h := &scoredMinHeap{}
+	heap.Init(h)
+	for _, s := range scored {
+		heap.Push(h, s)
+		if h.Len() > k {
+			heap.Pop(h)
+		}
+	}
+
+	result := make([]models.Observation, h.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(h).(scoredObservation).obs
+	}
+	return result
+}
@@ -0,0 +1,208 @@
+// Package db provides storage adapters for the Do Worker Service.
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+// Config holds the connection settings for a db.Adapter. Type selects which
+// adapter NewAdapter constructs ("mysql" or "postgres").
+type Config struct {
+	Type     string
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Database string
+}
+
+// ScopeContext identifies the scope (organization / shared workspace) a
+// request is acting within and the calling user's membership in it. Every
+// Adapter method that reads or writes scoped data takes one, so the
+// authorization check lives in the query itself rather than in each caller.
+type ScopeContext struct {
+	ScopeID  string
+	UserName string
+	Role     string
+}
+
+// ErrScopeForbidden is returned when ScopeContext.UserName is not a member
+// of ScopeContext.ScopeID.
+var ErrScopeForbidden = errors.New("db: user is not a member of this scope")
+
+// TokenInfo is the user and scopes bound to a bearer API token, as
+// resolved by Adapter.AuthenticateToken.
+type TokenInfo struct {
+	UserName string
+	Scopes   []string
+}
+
+// HasScope reports whether the token grants the requested scope. The
+// "admin" scope implies every other scope; a coarse scope (e.g. "read")
+// also implies any of its colon-qualified sub-scopes (e.g. "read:team").
+func (t *TokenInfo) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == "admin" || s == scope {
+			return true
+		}
+		if prefix := strings.SplitN(scope, ":", 2)[0]; s == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// hashAPIToken hashes a bearer token with SHA-256 so api_tokens never
+// stores (or leaks via a DB dump) the token itself.
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SearchMode selects the full-text query syntax used by
+// SearchObservationsRanked.
+type SearchMode int
+
+const (
+	// SearchNatural ranks by natural-language relevance and treats the
+	// query as free text (MySQL's NATURAL LANGUAGE MODE / Postgres
+	// plainto_tsquery).
+	SearchNatural SearchMode = iota
+	// SearchBoolean honors `+word`/`-word`/`"phrase"` operators (MySQL's
+	// BOOLEAN MODE / Postgres websearch_to_tsquery).
+	SearchBoolean
+)
+
+// SearchFilters narrows SearchObservationsRanked beyond the full-text match
+// itself. Zero values are treated as "no filter".
+type SearchFilters struct {
+	Mode          SearchMode
+	SessionID     string
+	UserName      string
+	Type          string
+	MinImportance int
+	From          *time.Time
+	To            *time.Time
+}
+
+// Adapter is the storage interface implemented by each supported database
+// engine (MySQL, Postgres, ...). Handlers in internal/server and
+// internal/context depend only on this interface, never on a concrete
+// engine type. Every method that touches sessions, observations, summaries,
+// or plans takes a ScopeContext and must enforce it in the query itself
+// (scope_id = ? plus scope membership), not merely in the caller.
+type Adapter interface {
+	Health(ctx context.Context) error
+	Close() error
+
+	CreateSession(ctx context.Context, scope ScopeContext, session *models.Session) error
+	GetSession(ctx context.Context, scope ScopeContext, id string) (*models.Session, error)
+	GetLatestSession(ctx context.Context, scope ScopeContext) (*models.Session, error)
+	EndSession(ctx context.Context, scope ScopeContext, id string, summary string) error
+	GetRecentSessions(ctx context.Context, scope ScopeContext, limit int) ([]models.Session, error)
+	// ListSessionsAfter keyset-paginates sessions newest-first. cursor is nil
+	// for the first page; pass the Cursor built from the last row of a page
+	// to fetch the next one.
+	ListSessionsAfter(ctx context.Context, scope ScopeContext, cursor *Cursor, limit int) ([]models.Session, error)
+
+	CreateObservation(ctx context.Context, scope ScopeContext, obs *models.Observation) error
+	// CreateObservationsBatch inserts every observation in a single
+	// transaction, setting each element's ID on success. It commits or
+	// rolls back as one unit, so callers that want partial-failure
+	// isolation must chunk large inputs themselves before calling it.
+	CreateObservationsBatch(ctx context.Context, scope ScopeContext, observations []*models.Observation) error
+	GetObservations(ctx context.Context, scope ScopeContext, sessionID string) ([]models.Observation, error)
+	GetRecentObservations(ctx context.Context, scope ScopeContext, limit int) ([]models.Observation, error)
+	GetObservationsFiltered(ctx context.Context, scope ScopeContext, sessionID string, obsType string, limit int) ([]models.Observation, error)
+	// ListObservationsAfter keyset-paginates observations newest-first,
+	// honoring the same sessionID/obsType filters as GetObservationsFiltered.
+	ListObservationsAfter(ctx context.Context, scope ScopeContext, sessionID string, obsType string, cursor *Cursor, limit int) ([]models.Observation, error)
+	SearchObservations(ctx context.Context, scope ScopeContext, query string, limit int) ([]models.Observation, error)
+	SearchObservationsRanked(ctx context.Context, scope ScopeContext, query string, filters SearchFilters, limit int) ([]models.ObservationHit, error)
+	GetObservationsByTags(ctx context.Context, scope ScopeContext, tags []string, matchAll bool, limit int) ([]models.Observation, error)
+
+	CreateSummary(ctx context.Context, scope ScopeContext, summary *models.Summary) error
+	GetSummaries(ctx context.Context, scope ScopeContext, summaryType string, limit int) ([]models.Summary, error)
+	GetAllSummaries(ctx context.Context, scope ScopeContext, days int, limit int) ([]models.Summary, error)
+	// ListSummariesAfter keyset-paginates summaries from the last `days`
+	// days, newest-first.
+	ListSummariesAfter(ctx context.Context, scope ScopeContext, days int, cursor *Cursor, limit int) ([]models.Summary, error)
+
+	CreatePlan(ctx context.Context, scope ScopeContext, plan *models.Plan) error
+	GetActivePlan(ctx context.Context, scope ScopeContext) (*models.Plan, error)
+	GetAllPlans(ctx context.Context, scope ScopeContext, sessionID string, limit int) ([]models.Plan, error)
+	// ListPlansAfter keyset-paginates plans newest-first, optionally scoped
+	// to sessionID.
+	ListPlansAfter(ctx context.Context, scope ScopeContext, sessionID string, cursor *Cursor, limit int) ([]models.Plan, error)
+	UpdatePlanStatus(ctx context.Context, scope ScopeContext, id int64, status string) error
+
+	GetTeamContext(ctx context.Context, scope ScopeContext) ([]models.TeamContext, error)
+	GetProjects(ctx context.Context, scope ScopeContext) ([]models.Project, error)
+
+	CreateScope(ctx context.Context, name string, ownerUser string) (*models.Scope, error)
+	AddScopeMember(ctx context.Context, scopeID string, userName string, role string) error
+	ListUserScopes(ctx context.Context, userName string) ([]models.Scope, error)
+	// GetScopeMemberRole returns userName's role within scopeID, or "" if
+	// they aren't a member. Used to authorize scope-management calls like
+	// AddScopeMember before granting someone membership in it.
+	GetScopeMemberRole(ctx context.Context, scopeID string, userName string) (string, error)
+	// EnsureDefaultScope idempotently provisions userName's own default
+	// scope ("default-<userName>") and owner membership in it. The scopes
+	// migration only backfills this for users who already had sessions at
+	// migration time, so anyone new has to be bootstrapped on first use;
+	// callers may invoke this on every request since it's a no-op once the
+	// rows exist.
+	EnsureDefaultScope(ctx context.Context, userName string) error
+
+	// AuthenticateToken resolves a bearer token to the user and scopes it
+	// grants. It returns (nil, nil), not an error, when the token doesn't
+	// match any row, so callers can tell "invalid token" apart from a
+	// transient DB failure.
+	AuthenticateToken(ctx context.Context, token string) (*TokenInfo, error)
+
+	// ListTags returns the distinct tags used within scope, most-used first,
+	// for autocomplete. An empty prefix matches every tag.
+	ListTags(ctx context.Context, scope ScopeContext, prefix string, limit int) ([]models.TagCount, error)
+	// RenameTag renames a tag across every observation that references it.
+	// Tags are a global vocabulary, not scoped, so the rename itself affects
+	// every scope using oldName — but the caller must be a member of a
+	// scope that actually uses oldName, or this is a no-op (ErrNoRows).
+	RenameTag(ctx context.Context, scope ScopeContext, oldName string, newName string) error
+}
+
+// StatsProvider is an optional capability implemented by adapters that can
+// compute dashboard aggregates as single SQL statements rather than pulling
+// raw rows and reducing them in Go. Not every engine backs it yet, so
+// callers should type-assert an Adapter to StatsProvider and degrade
+// gracefully when it's absent.
+type StatsProvider interface {
+	GetDailyActivity(ctx context.Context, scope ScopeContext, days int) ([]models.DailyActivity, error)
+	GetObservationTypeBreakdown(ctx context.Context, scope ScopeContext, sessionID string, days int) ([]models.ObservationTypeCount, error)
+	GetTopTags(ctx context.Context, scope ScopeContext, limit int) ([]models.TopTag, error)
+	GetPlanCompletionRate(ctx context.Context, scope ScopeContext, days int) (*models.PlanCompletionRate, error)
+}
+
+// NewAdapter constructs the Adapter matching cfg.Type.
+func NewAdapter(cfg Config) (Adapter, error) {
+	switch cfg.Type {
+	case "", "mysql":
+		return NewMySQL(cfg)
+	case "postgres", "postgresql":
+		return NewPostgres(cfg)
+	default:
+		return nil, unsupportedAdapterError(cfg.Type)
+	}
+}
+
+type unsupportedAdapterError string
+
+func (e unsupportedAdapterError) Error() string {
+	return "unsupported db adapter type: " + string(e)
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	migdatabase "github.com/golang-migrate/migrate/v4/database"
+	migmysql "github.com/golang-migrate/migrate/v4/database/mysql"
+	migpostgres "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+//go:embed migrations/mysql/*.sql
+var mysqlMigrations embed.FS
+
+//go:embed migrations/postgres/*.sql
+var postgresMigrations embed.FS
+
+// runMigrations applies all pending schema migrations for the given engine
+// ("mysql" or "postgres"), tracked in a schema_migrations table, using the
+// embedded .sql files shared by both adapters. It replaces the previous
+// per-engine initSchema/runMigrations pair with a single versioned path, so
+// new columns no longer need bespoke INFORMATION_SCHEMA probes.
+func runMigrations(sqlDB *sql.DB, engine string) error {
+	var (
+		driver migdatabase.Driver
+		srcFS  embed.FS
+		subdir string
+		err    error
+	)
+
+	switch engine {
+	case "mysql":
+		driver, err = migmysql.WithInstance(sqlDB, &migmysql.Config{})
+		srcFS, subdir = mysqlMigrations, "migrations/mysql"
+	case "postgres":
+		driver, err = migpostgres.WithInstance(sqlDB, &migpostgres.Config{})
+		srcFS, subdir = postgresMigrations, "migrations/postgres"
+	default:
+		return fmt.Errorf("unsupported migration engine: %s", engine)
+	}
+	if err != nil {
+		return fmt.Errorf("create %s migration driver: %w", engine, err)
+	}
+
+	src, err := iofs.New(srcFS, subdir)
+	if err != nil {
+		return fmt.Errorf("load embedded %s migrations: %w", engine, err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, engine, driver)
+	if err != nil {
+		return fmt.Errorf("init %s migrator: %w", engine, err)
+	}
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("apply %s migrations: %w", engine, err)
+	}
+
+	return nil
+}
@@ -5,15 +5,21 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/do-focus/worker/internal/db/queries"
 	"github.com/do-focus/worker/pkg/models"
 	_ "github.com/go-sql-driver/mysql"
 )
 
-// MySQL implements the Adapter interface for MySQL.
+// MySQL implements the Adapter interface for MySQL, delegating the actual
+// SQL to the sqlc-generated queries package rather than hand-written
+// strings, so schema changes only need regenerating that package.
 type MySQL struct {
 	db *sql.DB
+	q  *queries.Queries
 }
 
 // NewMySQL creates a new MySQL adapter.
@@ -31,96 +37,17 @@ func NewMySQL(cfg Config) (*MySQL, error) {
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(5 * time.Minute)
 
-	m := &MySQL{db: db}
+	m := &MySQL{db: db, q: queries.New(db)}
 
-	// Initialize schema
-	if err := m.initSchema(); err != nil {
+	// Apply the versioned schema migrations shared with the Postgres adapter.
+	if err := runMigrations(db, "mysql"); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
 	return m, nil
 }
 
-// initSchema creates the database tables if they don't exist.
-func (m *MySQL) initSchema() error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS sessions (
-		id VARCHAR(255) PRIMARY KEY,
-		user_name VARCHAR(255) NOT NULL,
-		project_id VARCHAR(500),
-		started_at DATETIME NOT NULL,
-		ended_at DATETIME,
-		summary TEXT,
-		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-		INDEX idx_sessions_user_name (user_name),
-		INDEX idx_sessions_started_at (started_at),
-		INDEX idx_sessions_project_id (project_id)
-	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
-	`
-	if _, err := m.db.Exec(schema); err != nil {
-		return err
-	}
-
-	// Run migrations for existing tables
-	if err := m.runMigrations(); err != nil {
-		return err
-	}
-
-	schema = `
-	CREATE TABLE IF NOT EXISTS observations (
-		id BIGINT AUTO_INCREMENT PRIMARY KEY,
-		session_id VARCHAR(255) NOT NULL,
-		agent_name VARCHAR(255),
-		type VARCHAR(50) NOT NULL,
-		content TEXT NOT NULL,
-		importance INT DEFAULT 3,
-		tags JSON,
-		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		INDEX idx_observations_session_id (session_id),
-		INDEX idx_observations_type (type),
-		INDEX idx_observations_importance (importance),
-		FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE CASCADE
-	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
-	`
-	if _, err := m.db.Exec(schema); err != nil {
-		return err
-	}
-
-	schema = `
-	CREATE TABLE IF NOT EXISTS summaries (
-		id BIGINT AUTO_INCREMENT PRIMARY KEY,
-		session_id VARCHAR(255),
-		type VARCHAR(50) NOT NULL,
-		content TEXT NOT NULL,
-		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		INDEX idx_summaries_type (type),
-		FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE SET NULL
-	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
-	`
-	if _, err := m.db.Exec(schema); err != nil {
-		return err
-	}
-
-	schema = `
-	CREATE TABLE IF NOT EXISTS plans (
-		id BIGINT AUTO_INCREMENT PRIMARY KEY,
-		session_id VARCHAR(255),
-		title VARCHAR(500) NOT NULL,
-		content TEXT NOT NULL,
-		status VARCHAR(50) NOT NULL DEFAULT 'draft',
-		file_path VARCHAR(1000),
-		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-		INDEX idx_plans_status (status),
-		FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE SET NULL
-	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
-	`
-	_, err := m.db.Exec(schema)
-	return err
-}
-
 // Health checks database connectivity.
 func (m *MySQL) Health(ctx context.Context) error {
 	return m.db.PingContext(ctx)
@@ -132,448 +59,783 @@ func (m *MySQL) Close() error {
 }
 
 // CreateSession creates a new session.
-func (m *MySQL) CreateSession(ctx context.Context, session *models.Session) error {
-	query := `
-		INSERT INTO sessions (id, user_name, project_id, started_at, created_at, updated_at)
-		VALUES (?, ?, ?, ?, NOW(), NOW())
-	`
-	_, err := m.db.ExecContext(ctx, query, session.ID, session.UserName, session.ProjectID, session.StartedAt)
-	return err
-}
-
-// GetSession retrieves a session by ID.
-func (m *MySQL) GetSession(ctx context.Context, id string) (*models.Session, error) {
-	query := `SELECT id, user_name, COALESCE(project_id, ''), started_at, ended_at, COALESCE(summary, ''), created_at, updated_at FROM sessions WHERE id = ?`
-	session := &models.Session{}
-	err := m.db.QueryRowContext(ctx, query, id).Scan(
-		&session.ID, &session.UserName, &session.ProjectID, &session.StartedAt, &session.EndedAt,
-		&session.Summary, &session.CreatedAt, &session.UpdatedAt,
-	)
+func (m *MySQL) CreateSession(ctx context.Context, scope ScopeContext, session *models.Session) error {
+	return m.q.CreateSession(ctx, queries.CreateSessionParams{
+		ID:        session.ID,
+		ScopeID:   scope.ScopeID,
+		UserName:  session.UserName,
+		ProjectID: nullString(session.ProjectID),
+		StartedAt: session.StartedAt,
+	})
+}
+
+// GetSession retrieves a session by ID within scope.
+func (m *MySQL) GetSession(ctx context.Context, scope ScopeContext, id string) (*models.Session, error) {
+	row, err := m.q.GetSession(ctx, queries.GetSessionParams{ID: id, ScopeID: scope.ScopeID, UserName: scope.UserName})
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
-	return session, err
-}
-
-// GetLatestSession retrieves the latest session for a user.
-func (m *MySQL) GetLatestSession(ctx context.Context, userName string) (*models.Session, error) {
-	query := `
-		SELECT id, user_name, started_at, ended_at, summary, created_at, updated_at
-		FROM sessions
-		WHERE user_name = ?
-		ORDER BY started_at DESC
-		LIMIT 1
-	`
-	session := &models.Session{}
-	err := m.db.QueryRowContext(ctx, query, userName).Scan(
-		&session.ID, &session.UserName, &session.StartedAt, &session.EndedAt,
-		&session.Summary, &session.CreatedAt, &session.UpdatedAt,
-	)
+	if err != nil {
+		return nil, err
+	}
+	return sessionFromRow(row), nil
+}
+
+// GetLatestSession retrieves the latest session for the scoped user.
+func (m *MySQL) GetLatestSession(ctx context.Context, scope ScopeContext) (*models.Session, error) {
+	row, err := m.q.GetLatestSession(ctx, queries.GetLatestSessionParams{UserName: scope.UserName, ScopeID: scope.ScopeID})
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
-	return session, err
+	if err != nil {
+		return nil, err
+	}
+	return sessionFromRow(row), nil
 }
 
 // EndSession ends a session with an optional summary.
-func (m *MySQL) EndSession(ctx context.Context, id string, summary string) error {
-	query := `UPDATE sessions SET ended_at = NOW(), summary = ? WHERE id = ?`
-	_, err := m.db.ExecContext(ctx, query, summary, id)
-	return err
-}
-
-// CreateObservation creates a new observation.
-func (m *MySQL) CreateObservation(ctx context.Context, obs *models.Observation) error {
-	query := `
-		INSERT INTO observations (session_id, agent_name, type, content, importance, tags, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, NOW())
-	`
-	result, err := m.db.ExecContext(ctx, query, obs.SessionID, obs.AgentName, obs.Type, obs.Content, obs.Importance, obs.Tags)
+func (m *MySQL) EndSession(ctx context.Context, scope ScopeContext, id string, summary string) error {
+	return m.q.EndSession(ctx, queries.EndSessionParams{Summary: summary, ID: id, ScopeID: scope.ScopeID, UserName: scope.UserName})
+}
+
+// GetRecentSessions retrieves recent sessions within scope.
+func (m *MySQL) GetRecentSessions(ctx context.Context, scope ScopeContext, limit int) ([]models.Session, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := m.q.GetRecentSessions(ctx, queries.GetRecentSessionsParams{ScopeID: scope.ScopeID, UserName: scope.UserName, Limit: int32(limit)})
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]models.Session, len(rows))
+	for i, row := range rows {
+		sessions[i] = *sessionFromRow(row)
+	}
+	return sessions, nil
+}
+
+// ListSessionsAfter keyset-paginates sessions newest-first.
+func (m *MySQL) ListSessionsAfter(ctx context.Context, scope ScopeContext, cursor *Cursor, limit int) ([]models.Session, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	hasCursor, lastCreatedAt, lastID := cursorParams(cursor)
+	rows, err := m.q.ListSessionsAfter(ctx, queries.ListSessionsAfterParams{
+		ScopeID:       scope.ScopeID,
+		UserName:      scope.UserName,
+		HasCursor:     hasCursor,
+		LastCreatedAt: lastCreatedAt,
+		LastID:        sql.NullString{String: lastID, Valid: hasCursor == 1},
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]models.Session, len(rows))
+	for i, row := range rows {
+		sessions[i] = *sessionFromRow(row)
+	}
+	return sessions, nil
+}
+
+// CreateObservation creates a new observation and upserts its tags into the
+// normalized tag tables within the same transaction. Scope membership is
+// verified via the parent session, since observations don't carry their own
+// scope_id.
+func (m *MySQL) CreateObservation(ctx context.Context, scope ScopeContext, obs *models.Observation) error {
+	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	id, err := result.LastInsertId()
-	if err == nil {
-		obs.ID = id
+	defer tx.Rollback()
+
+	qtx := m.q.WithTx(tx)
+	id, err := qtx.CreateObservation(ctx, queries.CreateObservationParams{
+		SessionID:  obs.SessionID,
+		AgentName:  nullString(obs.AgentName),
+		Type:       obs.Type,
+		Content:    obs.Content,
+		Importance: sql.NullInt32{Int32: int32(obs.Importance), Valid: true},
+		Tags:       nullString(obs.Tags),
+		ScopeID:    scope.ScopeID,
+		UserName:   scope.UserName,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := upsertObservationTags(ctx, qtx, id, obs.Tags); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
 	}
+	obs.ID = id
 	return nil
 }
 
-// GetObservations retrieves observations for a session.
-func (m *MySQL) GetObservations(ctx context.Context, sessionID string) ([]models.Observation, error) {
-	query := `
-		SELECT id, session_id, agent_name, type, content, importance, tags, created_at
-		FROM observations
-		WHERE session_id = ?
-		ORDER BY created_at DESC
-	`
-	rows, err := m.db.QueryContext(ctx, query, sessionID)
+// CreateObservationsBatch inserts observations in a single transaction,
+// mutating each element's ID on success. Used by the bulk ingestion
+// endpoint to avoid one round-trip per observation; the caller (the
+// bulk handler chunks into groups of 500) is responsible for sizing the
+// batch, since everything here commits or rolls back together.
+func (m *MySQL) CreateObservationsBatch(ctx context.Context, scope ScopeContext, observations []*models.Observation) error {
+	if len(observations) == 0 {
+		return nil
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	defer tx.Rollback()
+
+	qtx := m.q.WithTx(tx)
+	for _, obs := range observations {
+		id, err := qtx.CreateObservation(ctx, queries.CreateObservationParams{
+			SessionID:  obs.SessionID,
+			AgentName:  nullString(obs.AgentName),
+			Type:       obs.Type,
+			Content:    obs.Content,
+			Importance: sql.NullInt32{Int32: int32(obs.Importance), Valid: true},
+			Tags:       nullString(obs.Tags),
+			ScopeID:    scope.ScopeID,
+			UserName:   scope.UserName,
+		})
+		if err != nil {
+			return err
+		}
+		if err := upsertObservationTags(ctx, qtx, id, obs.Tags); err != nil {
+			return err
+		}
+		obs.ID = id
 	}
-	defer rows.Close()
 
-	var observations []models.Observation
-	for rows.Next() {
-		var obs models.Observation
-		if err := rows.Scan(&obs.ID, &obs.SessionID, &obs.AgentName, &obs.Type, &obs.Content, &obs.Importance, &obs.Tags, &obs.CreatedAt); err != nil {
-			return nil, err
+	return tx.Commit()
+}
+
+// upsertObservationTags decodes the JSON tags blob and links each tag to the
+// observation, creating tag rows as needed. The JSON column itself is left
+// untouched as a denormalized read cache.
+func upsertObservationTags(ctx context.Context, q *queries.Queries, observationID int64, tagsJSON string) error {
+	tags, err := parseTagsJSON(tagsJSON)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		tagID, err := q.UpsertTag(ctx, tag)
+		if err != nil {
+			return err
+		}
+		if err := q.LinkObservationTag(ctx, queries.LinkObservationTagParams{ObservationID: observationID, TagID: tagID}); err != nil {
+			return err
 		}
-		observations = append(observations, obs)
 	}
-	return observations, rows.Err()
+	return nil
 }
 
-// GetRecentObservations retrieves recent observations across sessions for a user.
-func (m *MySQL) GetRecentObservations(ctx context.Context, userName string, limit int) ([]models.Observation, error) {
-	query := `
-		SELECT o.id, o.session_id, o.agent_name, o.type, o.content, o.importance, o.tags, o.created_at
-		FROM observations o
-		JOIN sessions s ON o.session_id = s.id
-		WHERE s.user_name = ?
-		ORDER BY o.importance DESC, o.created_at DESC
-		LIMIT ?
-	`
-	rows, err := m.db.QueryContext(ctx, query, userName, limit)
+// GetObservations retrieves observations for a session within scope.
+func (m *MySQL) GetObservations(ctx context.Context, scope ScopeContext, sessionID string) ([]models.Observation, error) {
+	rows, err := m.q.GetObservations(ctx, queries.GetObservationsParams{SessionID: sessionID, ScopeID: scope.ScopeID, UserName: scope.UserName})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return observationsFromRows(rows), nil
+}
 
-	var observations []models.Observation
-	for rows.Next() {
-		var obs models.Observation
-		if err := rows.Scan(&obs.ID, &obs.SessionID, &obs.AgentName, &obs.Type, &obs.Content, &obs.Importance, &obs.Tags, &obs.CreatedAt); err != nil {
-			return nil, err
-		}
-		observations = append(observations, obs)
+// GetRecentObservations retrieves recent observations across sessions within scope.
+func (m *MySQL) GetRecentObservations(ctx context.Context, scope ScopeContext, limit int) ([]models.Observation, error) {
+	rows, err := m.q.GetRecentObservations(ctx, queries.GetRecentObservationsParams{ScopeID: scope.ScopeID, UserName: scope.UserName, Limit: int32(limit)})
+	if err != nil {
+		return nil, err
 	}
-	return observations, rows.Err()
+	return observationsFromRows(rows), nil
 }
 
-// GetObservationsFiltered retrieves observations with optional filters.
-func (m *MySQL) GetObservationsFiltered(ctx context.Context, sessionID string, obsType string, limit int) ([]models.Observation, error) {
-	query := `
-		SELECT id, session_id, COALESCE(agent_name, ''), type, content, importance, COALESCE(tags, ''), created_at
-		FROM observations
-		WHERE (? = '' OR session_id = ?) AND (? = '' OR type = ?)
-		ORDER BY created_at DESC
-		LIMIT ?
-	`
+// GetObservationsFiltered retrieves observations with optional filters, within scope.
+func (m *MySQL) GetObservationsFiltered(ctx context.Context, scope ScopeContext, sessionID string, obsType string, limit int) ([]models.Observation, error) {
 	if limit <= 0 {
 		limit = 100
 	}
-	rows, err := m.db.QueryContext(ctx, query, sessionID, sessionID, obsType, obsType, limit)
+	rows, err := m.q.GetObservationsFiltered(ctx, queries.GetObservationsFilteredParams{
+		SessionID: sessionID,
+		Type:      obsType,
+		ScopeID:   scope.ScopeID,
+		UserName:  scope.UserName,
+		Limit:     int32(limit),
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return observationsFromRows(rows), nil
+}
 
-	var observations []models.Observation
-	for rows.Next() {
-		var obs models.Observation
-		if err := rows.Scan(&obs.ID, &obs.SessionID, &obs.AgentName, &obs.Type, &obs.Content, &obs.Importance, &obs.Tags, &obs.CreatedAt); err != nil {
-			return nil, err
-		}
-		observations = append(observations, obs)
+// ListObservationsAfter keyset-paginates observations newest-first, honoring
+// the same sessionID/obsType filters as GetObservationsFiltered.
+func (m *MySQL) ListObservationsAfter(ctx context.Context, scope ScopeContext, sessionID string, obsType string, cursor *Cursor, limit int) ([]models.Observation, error) {
+	if limit <= 0 {
+		limit = 100
 	}
-	return observations, rows.Err()
+	hasCursor, lastCreatedAt, lastID := cursorParamsInt(cursor)
+	rows, err := m.q.ListObservationsAfter(ctx, queries.ListObservationsAfterParams{
+		SessionID:     sessionID,
+		Type:          obsType,
+		ScopeID:       scope.ScopeID,
+		UserName:      scope.UserName,
+		HasCursor:     hasCursor,
+		LastCreatedAt: lastCreatedAt,
+		LastID:        lastID,
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return observationsFromRows(rows), nil
 }
 
-// SearchObservations searches observations by content.
-func (m *MySQL) SearchObservations(ctx context.Context, query string, limit int) ([]models.Observation, error) {
-	sqlQuery := `
-		SELECT id, session_id, COALESCE(agent_name, ''), type, content, importance, COALESCE(tags, ''), created_at
-		FROM observations
-		WHERE content LIKE ?
-		ORDER BY importance DESC, created_at DESC
-		LIMIT ?
-	`
+// SearchObservations searches observations by content within scope.
+func (m *MySQL) SearchObservations(ctx context.Context, scope ScopeContext, query string, limit int) ([]models.Observation, error) {
 	if limit <= 0 {
 		limit = 50
 	}
-	searchPattern := "%" + query + "%"
-	rows, err := m.db.QueryContext(ctx, sqlQuery, searchPattern, limit)
+	rows, err := m.q.SearchObservations(ctx, queries.SearchObservationsParams{Query: query, ScopeID: scope.ScopeID, UserName: scope.UserName, Limit: int32(limit)})
+	if err != nil {
+		return nil, err
+	}
+	return observationsFromRows(rows), nil
+}
+
+// SearchObservationsRanked runs a full-text search with optional filters and
+// returns hits ordered by a blended relevance/importance/recency score. The
+// filter combinations here are too dynamic to express as fixed sqlc queries,
+// so the WHERE clause is assembled by hand, mirroring GetObservationsFiltered.
+func (m *MySQL) SearchObservationsRanked(ctx context.Context, scope ScopeContext, query string, filters SearchFilters, limit int) ([]models.ObservationHit, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	mode := "NATURAL LANGUAGE"
+	if filters.Mode == SearchBoolean {
+		mode = "BOOLEAN"
+	}
+
+	conditions := []string{
+		fmt.Sprintf("MATCH(o.content) AGAINST (? IN %s MODE)", mode),
+		"s.scope_id = ?",
+		"EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = ? AND sm.user_name = ?)",
+	}
+	args := []interface{}{query, scope.ScopeID, scope.ScopeID, scope.UserName}
+
+	if filters.UserName != "" {
+		conditions = append(conditions, "s.user_name = ?")
+		args = append(args, filters.UserName)
+	}
+	if filters.SessionID != "" {
+		conditions = append(conditions, "o.session_id = ?")
+		args = append(args, filters.SessionID)
+	}
+	if filters.Type != "" {
+		conditions = append(conditions, "o.type = ?")
+		args = append(args, filters.Type)
+	}
+	if filters.MinImportance > 0 {
+		conditions = append(conditions, "o.importance >= ?")
+		args = append(args, filters.MinImportance)
+	}
+	if filters.From != nil {
+		conditions = append(conditions, "o.created_at >= ?")
+		args = append(args, *filters.From)
+	}
+	if filters.To != nil {
+		conditions = append(conditions, "o.created_at <= ?")
+		args = append(args, *filters.To)
+	}
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT o.id, o.session_id, COALESCE(o.agent_name, ''), o.type, o.content, o.importance, COALESCE(o.tags, ''), o.created_at,
+			MATCH(o.content) AGAINST (? IN %s MODE) AS relevance
+		FROM observations o
+		JOIN sessions s ON o.session_id = s.id
+		WHERE %s
+		ORDER BY relevance DESC
+		LIMIT ?
+	`, mode, strings.Join(conditions, " AND "))
+
+	queryArgs := append([]interface{}{query}, args...)
+	queryArgs = append(queryArgs, limit)
+
+	rows, err := m.db.QueryContext(ctx, sqlQuery, queryArgs...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var observations []models.Observation
+	var hits []models.ObservationHit
 	for rows.Next() {
 		var obs models.Observation
-		if err := rows.Scan(&obs.ID, &obs.SessionID, &obs.AgentName, &obs.Type, &obs.Content, &obs.Importance, &obs.Tags, &obs.CreatedAt); err != nil {
+		var relevance float64
+		if err := rows.Scan(&obs.ID, &obs.SessionID, &obs.AgentName, &obs.Type, &obs.Content, &obs.Importance, &obs.Tags, &obs.CreatedAt, &relevance); err != nil {
 			return nil, err
 		}
-		observations = append(observations, obs)
+		hits = append(hits, models.ObservationHit{
+			Observation: obs,
+			Score:       combinedSearchScore(relevance, obs.Importance, obs.CreatedAt),
+		})
 	}
-	return observations, rows.Err()
+	return hits, rows.Err()
 }
 
-// CreateSummary creates a new summary.
-func (m *MySQL) CreateSummary(ctx context.Context, summary *models.Summary) error {
-	query := `INSERT INTO summaries (session_id, type, content, created_at) VALUES (?, ?, ?, NOW())`
-	result, err := m.db.ExecContext(ctx, query, summary.SessionID, summary.Type, summary.Content)
+// CreateSummary creates a new summary within scope.
+func (m *MySQL) CreateSummary(ctx context.Context, scope ScopeContext, summary *models.Summary) error {
+	mode := summary.Mode
+	if mode == "" {
+		mode = "rule"
+	}
+	id, err := m.q.CreateSummary(ctx, queries.CreateSummaryParams{
+		ScopeID:          scope.ScopeID,
+		SessionID:        nullString(summary.SessionID),
+		Type:             summary.Type,
+		Content:          summary.Content,
+		Mode:             mode,
+		Provider:         nullString(summary.Provider),
+		PromptTokens:     int32(summary.PromptTokens),
+		CompletionTokens: int32(summary.CompletionTokens),
+	})
 	if err != nil {
 		return err
 	}
-	id, err := result.LastInsertId()
-	if err == nil {
-		summary.ID = id
-	}
+	summary.ID = id
+	summary.Mode = mode
 	return nil
 }
 
-// GetSummaries retrieves summaries by type.
-func (m *MySQL) GetSummaries(ctx context.Context, summaryType string, limit int) ([]models.Summary, error) {
-	query := `
-		SELECT id, session_id, type, content, created_at
-		FROM summaries
-		WHERE type = ?
-		ORDER BY created_at DESC
-		LIMIT ?
-	`
-	rows, err := m.db.QueryContext(ctx, query, summaryType, limit)
+// GetSummaries retrieves summaries by type within scope.
+func (m *MySQL) GetSummaries(ctx context.Context, scope ScopeContext, summaryType string, limit int) ([]models.Summary, error) {
+	rows, err := m.q.GetSummaries(ctx, queries.GetSummariesParams{Type: summaryType, ScopeID: scope.ScopeID, UserName: scope.UserName, Limit: int32(limit)})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var summaries []models.Summary
-	for rows.Next() {
-		var sum models.Summary
-		if err := rows.Scan(&sum.ID, &sum.SessionID, &sum.Type, &sum.Content, &sum.CreatedAt); err != nil {
-			return nil, err
-		}
-		summaries = append(summaries, sum)
-	}
-	return summaries, rows.Err()
+	return summariesFromRows(rows), nil
 }
 
-// GetAllSummaries retrieves all summaries within a date range.
-func (m *MySQL) GetAllSummaries(ctx context.Context, days int, limit int) ([]models.Summary, error) {
+// GetAllSummaries retrieves all summaries within a date range and scope.
+func (m *MySQL) GetAllSummaries(ctx context.Context, scope ScopeContext, days int, limit int) ([]models.Summary, error) {
 	if days <= 0 {
 		days = 7
 	}
 	if limit <= 0 {
 		limit = 100
 	}
-	query := `
-		SELECT id, COALESCE(session_id, ''), type, content, created_at
-		FROM summaries
-		WHERE created_at >= DATE_SUB(NOW(), INTERVAL ? DAY)
-		ORDER BY created_at DESC
-		LIMIT ?
-	`
-	rows, err := m.db.QueryContext(ctx, query, days, limit)
+	rows, err := m.q.GetAllSummaries(ctx, queries.GetAllSummariesParams{Days: int32(days), ScopeID: scope.ScopeID, UserName: scope.UserName, Limit: int32(limit)})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return summariesFromRows(rows), nil
+}
 
-	var summaries []models.Summary
-	for rows.Next() {
-		var sum models.Summary
-		if err := rows.Scan(&sum.ID, &sum.SessionID, &sum.Type, &sum.Content, &sum.CreatedAt); err != nil {
-			return nil, err
-		}
-		summaries = append(summaries, sum)
+// ListSummariesAfter keyset-paginates summaries from the last `days` days,
+// newest-first.
+func (m *MySQL) ListSummariesAfter(ctx context.Context, scope ScopeContext, days int, cursor *Cursor, limit int) ([]models.Summary, error) {
+	if days <= 0 {
+		days = 7
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	hasCursor, lastCreatedAt, lastID := cursorParamsInt(cursor)
+	rows, err := m.q.ListSummariesAfter(ctx, queries.ListSummariesAfterParams{
+		Days:          int32(days),
+		ScopeID:       scope.ScopeID,
+		UserName:      scope.UserName,
+		HasCursor:     hasCursor,
+		LastCreatedAt: lastCreatedAt,
+		LastID:        lastID,
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		return nil, err
 	}
-	return summaries, rows.Err()
+	return summariesFromRows(rows), nil
 }
 
-// CreatePlan creates a new plan.
-func (m *MySQL) CreatePlan(ctx context.Context, plan *models.Plan) error {
-	query := `
-		INSERT INTO plans (session_id, title, content, status, file_path, created_at, updated_at)
-		VALUES (?, ?, ?, 'draft', ?, NOW(), NOW())
-	`
-	result, err := m.db.ExecContext(ctx, query, plan.SessionID, plan.Title, plan.Content, plan.FilePath)
+// CreatePlan creates a new plan within scope.
+func (m *MySQL) CreatePlan(ctx context.Context, scope ScopeContext, plan *models.Plan) error {
+	id, err := m.q.CreatePlan(ctx, queries.CreatePlanParams{
+		ScopeID:   scope.ScopeID,
+		SessionID: nullString(plan.SessionID),
+		Title:     plan.Title,
+		Content:   plan.Content,
+		FilePath:  nullString(plan.FilePath),
+	})
 	if err != nil {
 		return err
 	}
-	id, err := result.LastInsertId()
-	if err == nil {
-		plan.ID = id
-	}
+	plan.ID = id
 	return nil
 }
 
-// GetActivePlan retrieves the active plan for a user.
-func (m *MySQL) GetActivePlan(ctx context.Context, userName string) (*models.Plan, error) {
-	query := `
-		SELECT p.id, p.session_id, p.title, p.content, p.status, p.file_path, p.created_at, p.updated_at
-		FROM plans p
-		JOIN sessions s ON p.session_id = s.id
-		WHERE s.user_name = ? AND p.status = 'active'
-		ORDER BY p.updated_at DESC
-		LIMIT 1
-	`
-	plan := &models.Plan{}
-	err := m.db.QueryRowContext(ctx, query, userName).Scan(
-		&plan.ID, &plan.SessionID, &plan.Title, &plan.Content,
-		&plan.Status, &plan.FilePath, &plan.CreatedAt, &plan.UpdatedAt,
-	)
+// GetActivePlan retrieves the active plan within scope.
+func (m *MySQL) GetActivePlan(ctx context.Context, scope ScopeContext) (*models.Plan, error) {
+	row, err := m.q.GetActivePlan(ctx, queries.GetActivePlanParams{ScopeID: scope.ScopeID, UserName: scope.UserName})
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
-	return plan, err
+	if err != nil {
+		return nil, err
+	}
+	return planFromRow(row), nil
 }
 
-// GetAllPlans retrieves all plans with optional session filter.
-func (m *MySQL) GetAllPlans(ctx context.Context, sessionID string, limit int) ([]models.Plan, error) {
+// GetAllPlans retrieves all plans with optional session filter, within scope.
+func (m *MySQL) GetAllPlans(ctx context.Context, scope ScopeContext, sessionID string, limit int) ([]models.Plan, error) {
 	if limit <= 0 {
 		limit = 50
 	}
-	query := `
-		SELECT id, COALESCE(session_id, ''), title, content, status, COALESCE(file_path, ''), created_at, updated_at
-		FROM plans
-		WHERE ? = '' OR session_id = ?
-		ORDER BY updated_at DESC
-		LIMIT ?
-	`
-	rows, err := m.db.QueryContext(ctx, query, sessionID, sessionID, limit)
+	rows, err := m.q.GetAllPlans(ctx, queries.GetAllPlansParams{SessionID: sessionID, ScopeID: scope.ScopeID, UserName: scope.UserName, Limit: int32(limit)})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	plans := make([]models.Plan, len(rows))
+	for i, row := range rows {
+		plans[i] = *planFromRow(row)
+	}
+	return plans, nil
+}
 
-	var plans []models.Plan
-	for rows.Next() {
-		var plan models.Plan
-		if err := rows.Scan(&plan.ID, &plan.SessionID, &plan.Title, &plan.Content, &plan.Status, &plan.FilePath, &plan.CreatedAt, &plan.UpdatedAt); err != nil {
-			return nil, err
+// ListPlansAfter keyset-paginates plans newest-first by created_at,
+// optionally scoped to sessionID.
+func (m *MySQL) ListPlansAfter(ctx context.Context, scope ScopeContext, sessionID string, cursor *Cursor, limit int) ([]models.Plan, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	hasCursor, lastCreatedAt, lastID := cursorParamsInt(cursor)
+	rows, err := m.q.ListPlansAfter(ctx, queries.ListPlansAfterParams{
+		SessionID:     sessionID,
+		ScopeID:       scope.ScopeID,
+		UserName:      scope.UserName,
+		HasCursor:     hasCursor,
+		LastCreatedAt: lastCreatedAt,
+		LastID:        lastID,
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	plans := make([]models.Plan, len(rows))
+	for i, row := range rows {
+		plans[i] = *planFromRow(row)
+	}
+	return plans, nil
+}
+
+// UpdatePlanStatus updates a plan's status within scope.
+func (m *MySQL) UpdatePlanStatus(ctx context.Context, scope ScopeContext, id int64, status string) error {
+	return m.q.UpdatePlanStatus(ctx, queries.UpdatePlanStatusParams{Status: status, ID: id, ScopeID: scope.ScopeID, UserName: scope.UserName})
+}
+
+// GetTeamContext retrieves context from other members of the scope.
+func (m *MySQL) GetTeamContext(ctx context.Context, scope ScopeContext) ([]models.TeamContext, error) {
+	rows, err := m.q.GetTeamContext(ctx, queries.GetTeamContextParams{ScopeID: scope.ScopeID, UserName: scope.UserName})
+	if err != nil {
+		return nil, err
+	}
+	contexts := make([]models.TeamContext, len(rows))
+	for i, row := range rows {
+		contexts[i] = models.TeamContext{
+			UserName:     row.UserName,
+			LastActivity: row.LastActivity,
+			Summary:      row.Summary,
+			ActivePlan:   row.ActivePlan,
+		}
+	}
+	return contexts, nil
+}
+
+// GetProjects retrieves all registered projects within scope with session statistics.
+func (m *MySQL) GetProjects(ctx context.Context, scope ScopeContext) ([]models.Project, error) {
+	rows, err := m.q.GetProjects(ctx, queries.GetProjectsParams{ScopeID: scope.ScopeID, UserName: scope.UserName})
+	if err != nil {
+		return nil, err
+	}
+	projects := make([]models.Project, len(rows))
+	for i, row := range rows {
+		projects[i] = models.Project{
+			ID:           row.ProjectID,
+			Path:         row.Path,
+			SessionCount: int(row.SessionCount),
+			LastActivity: row.LastActivity,
+		}
+	}
+	return projects, nil
+}
+
+// CreateScope creates a new scope and registers its owner as the first member.
+func (m *MySQL) CreateScope(ctx context.Context, name string, ownerUser string) (*models.Scope, error) {
+	id := fmt.Sprintf("scope-%s-%d", strings.ToLower(strings.ReplaceAll(name, " ", "-")), time.Now().UnixNano())
+	if err := m.q.CreateScope(ctx, queries.CreateScopeParams{ID: id, Name: name, OwnerUser: ownerUser}); err != nil {
+		return nil, err
+	}
+	if err := m.q.AddScopeMember(ctx, queries.AddScopeMemberParams{ScopeID: id, UserName: ownerUser, Role: "owner"}); err != nil {
+		return nil, err
+	}
+	return &models.Scope{ID: id, Name: name, OwnerUser: ownerUser, CreatedAt: time.Now()}, nil
+}
+
+// AddScopeMember adds or updates a user's membership and role within a scope.
+func (m *MySQL) AddScopeMember(ctx context.Context, scopeID string, userName string, role string) error {
+	return m.q.AddScopeMember(ctx, queries.AddScopeMemberParams{ScopeID: scopeID, UserName: userName, Role: role})
+}
+
+// GetScopeMemberRole returns userName's role within scopeID, or "" if they
+// aren't a member of it.
+func (m *MySQL) GetScopeMemberRole(ctx context.Context, scopeID string, userName string) (string, error) {
+	role, err := m.q.GetScopeMemberRole(ctx, scopeID, userName)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return role, nil
+}
+
+// EnsureDefaultScope idempotently provisions userName's default scope and
+// owner membership in it, for users who signed up after the scopes
+// migration's one-time backfill ran.
+func (m *MySQL) EnsureDefaultScope(ctx context.Context, userName string) error {
+	scopeID := "default-" + userName
+	if err := m.q.EnsureScope(ctx, queries.EnsureScopeParams{ID: scopeID, Name: userName + "'s workspace", OwnerUser: userName}); err != nil {
+		return err
+	}
+	return m.q.EnsureScopeMember(ctx, queries.EnsureScopeMemberParams{ScopeID: scopeID, UserName: userName, Role: "owner"})
+}
+
+// ListUserScopes lists every scope a user belongs to, ordered by creation.
+func (m *MySQL) ListUserScopes(ctx context.Context, userName string) ([]models.Scope, error) {
+	rows, err := m.q.ListUserScopes(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+	scopes := make([]models.Scope, len(rows))
+	for i, row := range rows {
+		scopes[i] = models.Scope{
+			ID:        row.ID,
+			Name:      row.Name,
+			OwnerUser: row.OwnerUser,
+			CreatedAt: row.CreatedAt,
 		}
-		plans = append(plans, plan)
 	}
-	return plans, rows.Err()
+	return scopes, nil
 }
 
-// UpdatePlanStatus updates a plan's status.
-func (m *MySQL) UpdatePlanStatus(ctx context.Context, id int64, status string) error {
-	query := `UPDATE plans SET status = ? WHERE id = ?`
-	_, err := m.db.ExecContext(ctx, query, status, id)
-	return err
+// AuthenticateToken resolves a bearer token to the user and scopes it
+// grants by looking up its SHA-256 hash in api_tokens, and best-effort
+// records the token's last use.
+func (m *MySQL) AuthenticateToken(ctx context.Context, token string) (*TokenInfo, error) {
+	hash := hashAPIToken(token)
+	row, err := m.q.GetAPIToken(ctx, hash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = m.q.TouchAPIToken(ctx, hash)
+	return &TokenInfo{UserName: row.UserName, Scopes: strings.Split(row.Scopes, ",")}, nil
 }
 
-// GetRecentSessions retrieves recent sessions.
-func (m *MySQL) GetRecentSessions(ctx context.Context, limit int) ([]models.Session, error) {
+// GetObservationsByTags retrieves observations tagged with any (or, with
+// matchAll, all) of tags within scope, most recent first. The placeholder
+// list for the IN clause is sized to len(tags) since sqlc can't express a
+// variadic IN.
+func (m *MySQL) GetObservationsByTags(ctx context.Context, scope ScopeContext, tags []string, matchAll bool, limit int) ([]models.Observation, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
 	if limit <= 0 {
-		limit = 20
+		limit = 50
 	}
-	query := `
-		SELECT id, user_name, started_at, ended_at, COALESCE(summary, ''), created_at, updated_at
-		FROM sessions
-		ORDER BY started_at DESC
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(tags)), ",")
+	args := make([]interface{}, 0, len(tags)+4)
+	args = append(args, scope.ScopeID, scope.ScopeID, scope.UserName)
+	for _, t := range tags {
+		args = append(args, t)
+	}
+
+	having := ""
+	if matchAll {
+		having = "HAVING COUNT(DISTINCT t.name) = " + fmt.Sprint(len(tags))
+	}
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT o.id, o.session_id, COALESCE(o.agent_name, ''), o.type, o.content, o.importance, COALESCE(o.tags, ''), o.created_at
+		FROM observations o
+		JOIN sessions s ON o.session_id = s.id
+		JOIN observation_tags ot ON ot.observation_id = o.id
+		JOIN tags t ON t.id = ot.tag_id
+		WHERE s.scope_id = ?
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = ? AND sm.user_name = ?)
+			AND t.name IN (%s)
+		GROUP BY o.id
+		%s
+		ORDER BY o.created_at DESC
 		LIMIT ?
-	`
-	rows, err := m.db.QueryContext(ctx, query, limit)
+	`, placeholders, having)
+	args = append(args, limit)
+
+	rows, err := m.db.QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var sessions []models.Session
+	var observations []models.Observation
 	for rows.Next() {
-		var session models.Session
-		if err := rows.Scan(&session.ID, &session.UserName, &session.StartedAt, &session.EndedAt, &session.Summary, &session.CreatedAt, &session.UpdatedAt); err != nil {
+		var obs models.Observation
+		if err := rows.Scan(&obs.ID, &obs.SessionID, &obs.AgentName, &obs.Type, &obs.Content, &obs.Importance, &obs.Tags, &obs.CreatedAt); err != nil {
 			return nil, err
 		}
-		sessions = append(sessions, session)
-	}
-	return sessions, rows.Err()
-}
-
-// GetTeamContext retrieves context from other team members.
-func (m *MySQL) GetTeamContext(ctx context.Context, excludeUser string) ([]models.TeamContext, error) {
-	query := `
-		SELECT
-			s.user_name,
-			MAX(s.started_at) as last_activity,
-			COALESCE(s.summary, '') as summary,
-			COALESCE(p.title, '') as active_plan
-		FROM sessions s
-		LEFT JOIN plans p ON p.session_id = s.id AND p.status = 'active'
-		WHERE s.user_name != ? AND s.ended_at IS NOT NULL
-		GROUP BY s.user_name
-		ORDER BY last_activity DESC
-		LIMIT 10
-	`
-	rows, err := m.db.QueryContext(ctx, query, excludeUser)
+		observations = append(observations, obs)
+	}
+	return observations, rows.Err()
+}
+
+// ListTags returns the distinct tags used within scope, most-used first.
+func (m *MySQL) ListTags(ctx context.Context, scope ScopeContext, prefix string, limit int) ([]models.TagCount, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := m.q.ListTags(ctx, queries.ListTagsParams{
+		ScopeID:  scope.ScopeID,
+		Prefix:   prefix,
+		UserName: scope.UserName,
+		Limit:    int32(limit),
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var contexts []models.TeamContext
-	for rows.Next() {
-		var tc models.TeamContext
-		if err := rows.Scan(&tc.UserName, &tc.LastActivity, &tc.Summary, &tc.ActivePlan); err != nil {
-			return nil, err
-		}
-		contexts = append(contexts, tc)
+	tags := make([]models.TagCount, len(rows))
+	for i, row := range rows {
+		tags[i] = models.TagCount{Name: row.Name, Count: int(row.Count)}
 	}
-	return contexts, rows.Err()
+	return tags, nil
 }
 
-// runMigrations applies schema migrations for existing databases.
-func (m *MySQL) runMigrations() error {
-	// Check if project_id column exists and add if not
-	var columnExists int
-	err := m.db.QueryRow(`
-		SELECT COUNT(*) FROM INFORMATION_SCHEMA.COLUMNS
-		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'sessions' AND COLUMN_NAME = 'project_id'
-	`).Scan(&columnExists)
+// RenameTag renames a tag across every observation that references it, but
+// only if scope is a member of a scope that actually uses oldName — tags
+// are a shared global table, so without this check any token holder could
+// rename a tag belonging to another tenant's observations.
+func (m *MySQL) RenameTag(ctx context.Context, scope ScopeContext, oldName string, newName string) error {
+	rows, err := m.q.RenameTag(ctx, queries.RenameTagParams{
+		Name:     newName,
+		Name_2:   oldName,
+		ScopeID:  scope.ScopeID,
+		UserName: scope.UserName,
+	})
 	if err != nil {
 		return err
 	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
 
-	if columnExists == 0 {
-		// Add project_id column to sessions
-		if _, err := m.db.Exec(`ALTER TABLE sessions ADD COLUMN project_id VARCHAR(500) AFTER user_name`); err != nil {
-			return fmt.Errorf("failed to add project_id to sessions: %w", err)
-		}
-		// Add index
-		if _, err := m.db.Exec(`CREATE INDEX idx_sessions_project_id ON sessions(project_id)`); err != nil {
-			// Index might already exist, ignore error
-		}
-		// Migrate existing data
-		if _, err := m.db.Exec(`UPDATE sessions SET project_id = user_name WHERE project_id IS NULL OR project_id = ''`); err != nil {
-			return fmt.Errorf("failed to migrate project_id data: %w", err)
-		}
+// nullString wraps s as a valid sql.NullString, treating the empty string
+// the same as SQL NULL to match the COALESCE(..., '') conventions baked
+// into the generated queries.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// cursorParams translates a Cursor into the (hasCursor, lastCreatedAt,
+// lastID) triple bound into every List*After query's "? = 0 OR (created_at,
+// id) < (?, ?)" guard. hasCursor is 0 for the first page, which short-
+// circuits the guard so the bound LastCreatedAt/LastID are ignored.
+func cursorParams(cursor *Cursor) (hasCursor int32, lastCreatedAt sql.NullTime, lastID string) {
+	if cursor == nil {
+		return 0, sql.NullTime{}, ""
 	}
+	return 1, sql.NullTime{Time: cursor.LastCreatedAt, Valid: true}, cursor.LastID
+}
 
-	return nil
+// cursorParamsInt is cursorParams for entities keyed by an int64 id
+// (observations, summaries, plans) rather than a string id (sessions).
+func cursorParamsInt(cursor *Cursor) (hasCursor int32, lastCreatedAt sql.NullTime, lastID sql.NullInt64) {
+	if cursor == nil {
+		return 0, sql.NullTime{}, sql.NullInt64{}
+	}
+	id, _ := strconv.ParseInt(cursor.LastID, 10, 64)
+	return 1, sql.NullTime{Time: cursor.LastCreatedAt, Valid: true}, sql.NullInt64{Int64: id, Valid: true}
 }
 
-// GetProjects retrieves all registered projects with session statistics.
-func (m *MySQL) GetProjects(ctx context.Context) ([]models.Project, error) {
-	query := `
-		SELECT
-			project_id,
-			project_id as path,
-			COUNT(*) as session_count,
-			MAX(started_at) as last_activity
-		FROM sessions
-		WHERE project_id IS NOT NULL AND project_id != ''
-		GROUP BY project_id
-		ORDER BY last_activity DESC
-	`
-	rows, err := m.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
+func sessionFromRow(row queries.Session) *models.Session {
+	session := &models.Session{
+		ID:        row.ID,
+		ScopeID:   row.ScopeID,
+		UserName:  row.UserName,
+		ProjectID: row.ProjectID.String,
+		StartedAt: row.StartedAt,
+		Summary:   row.Summary.String,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if row.EndedAt.Valid {
+		session.EndedAt = &row.EndedAt.Time
+	}
+	return session
+}
+
+func observationsFromRows(rows []queries.Observation) []models.Observation {
+	observations := make([]models.Observation, len(rows))
+	for i, row := range rows {
+		observations[i] = models.Observation{
+			ID:         row.ID,
+			SessionID:  row.SessionID,
+			AgentName:  row.AgentName.String,
+			Type:       row.Type,
+			Content:    row.Content,
+			Importance: int(row.Importance.Int32),
+			Tags:       row.Tags.String,
+			CreatedAt:  row.CreatedAt,
+		}
 	}
-	defer rows.Close()
+	return observations
+}
 
-	var projects []models.Project
-	for rows.Next() {
-		var p models.Project
-		if err := rows.Scan(&p.ID, &p.Path, &p.SessionCount, &p.LastActivity); err != nil {
-			return nil, err
+func summariesFromRows(rows []queries.Summary) []models.Summary {
+	summaries := make([]models.Summary, len(rows))
+	for i, row := range rows {
+		summaries[i] = models.Summary{
+			ID:               row.ID,
+			ScopeID:          row.ScopeID,
+			SessionID:        row.SessionID.String,
+			Type:             row.Type,
+			Content:          row.Content,
+			Mode:             row.Mode,
+			Provider:         row.Provider.String,
+			PromptTokens:     int(row.PromptTokens),
+			CompletionTokens: int(row.CompletionTokens),
+			CreatedAt:        row.CreatedAt,
 		}
-		projects = append(projects, p)
 	}
-	return projects, rows.Err()
+	return summaries
+}
+
+func planFromRow(row queries.Plan) *models.Plan {
+	return &models.Plan{
+		ID:        row.ID,
+		ScopeID:   row.ScopeID,
+		SessionID: row.SessionID.String,
+		Title:     row.Title,
+		Content:   row.Content,
+		Status:    row.Status,
+		FilePath:  row.FilePath.String,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
 }
 
 // mysqlTagsToJSON converts a slice of strings to a JSON string.
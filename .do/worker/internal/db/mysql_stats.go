@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+// GetDailyActivity buckets a scope's sessions and observations by calendar
+// day over the last `days` days. MySQL implements StatsProvider.
+func (m *MySQL) GetDailyActivity(ctx context.Context, scope ScopeContext, days int) ([]models.DailyActivity, error) {
+	if days <= 0 {
+		days = 30
+	}
+	query := `
+		SELECT DATE(s.started_at) AS day, COUNT(DISTINCT s.id) AS session_count,
+			COUNT(o.id) AS observation_count, COALESCE(AVG(o.importance), 0) AS avg_importance
+		FROM sessions s
+		LEFT JOIN observations o ON o.session_id = s.id
+		WHERE s.scope_id = ? AND s.started_at >= DATE_SUB(CURDATE(), INTERVAL ? DAY)
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = ? AND sm.user_name = ?)
+		GROUP BY DATE(s.started_at)
+		ORDER BY day DESC
+	`
+	rows, err := m.db.QueryContext(ctx, query, scope.ScopeID, days, scope.ScopeID, scope.UserName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activity []models.DailyActivity
+	for rows.Next() {
+		var a models.DailyActivity
+		if err := rows.Scan(&a.Date, &a.SessionCount, &a.ObservationCount, &a.AvgImportance); err != nil {
+			return nil, err
+		}
+		activity = append(activity, a)
+	}
+	return activity, rows.Err()
+}
+
+// GetObservationTypeBreakdown counts observations per type within a scope,
+// optionally narrowed to a session, over the last `days` days.
+func (m *MySQL) GetObservationTypeBreakdown(ctx context.Context, scope ScopeContext, sessionID string, days int) ([]models.ObservationTypeCount, error) {
+	if days <= 0 {
+		days = 30
+	}
+	query := `
+		SELECT o.type, COUNT(*) AS count
+		FROM observations o
+		JOIN sessions s ON o.session_id = s.id
+		WHERE s.scope_id = ? AND (? = '' OR o.session_id = ?)
+			AND o.created_at >= DATE_SUB(NOW(), INTERVAL ? DAY)
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = ? AND sm.user_name = ?)
+		GROUP BY o.type
+		ORDER BY count DESC
+	`
+	rows, err := m.db.QueryContext(ctx, query, scope.ScopeID, sessionID, sessionID, days, scope.ScopeID, scope.UserName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breakdown []models.ObservationTypeCount
+	for rows.Next() {
+		var b models.ObservationTypeCount
+		if err := rows.Scan(&b.Type, &b.Count); err != nil {
+			return nil, err
+		}
+		breakdown = append(breakdown, b)
+	}
+	return breakdown, rows.Err()
+}
+
+// GetTopTags unpacks each observation's JSON tags array via JSON_TABLE
+// (MySQL 8+) and returns the most frequently used tags within a scope.
+func (m *MySQL) GetTopTags(ctx context.Context, scope ScopeContext, limit int) ([]models.TopTag, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	query := `
+		SELECT jt.tag, COUNT(*) AS count
+		FROM observations o
+		JOIN sessions s ON o.session_id = s.id
+		JOIN JSON_TABLE(o.tags, '$[*]' COLUMNS (tag VARCHAR(255) PATH '$')) AS jt
+		WHERE s.scope_id = ? AND o.tags IS NOT NULL AND o.tags != ''
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = ? AND sm.user_name = ?)
+		GROUP BY jt.tag
+		ORDER BY count DESC
+		LIMIT ?
+	`
+	rows, err := m.db.QueryContext(ctx, query, scope.ScopeID, scope.ScopeID, scope.UserName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.TopTag
+	for rows.Next() {
+		var t models.TopTag
+		if err := rows.Scan(&t.Tag, &t.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// GetPlanCompletionRate returns draft/active/completed plan counts and the
+// completed ratio for a scope over the last `days` days.
+func (m *MySQL) GetPlanCompletionRate(ctx context.Context, scope ScopeContext, days int) (*models.PlanCompletionRate, error) {
+	if days <= 0 {
+		days = 30
+	}
+	query := `
+		SELECT
+			SUM(CASE WHEN p.status = 'draft' THEN 1 ELSE 0 END) AS draft,
+			SUM(CASE WHEN p.status = 'active' THEN 1 ELSE 0 END) AS active,
+			SUM(CASE WHEN p.status = 'completed' THEN 1 ELSE 0 END) AS completed,
+			COUNT(*) AS total
+		FROM plans p
+		WHERE p.scope_id = ? AND p.created_at >= DATE_SUB(NOW(), INTERVAL ? DAY)
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = ? AND sm.user_name = ?)
+	`
+	rate := &models.PlanCompletionRate{}
+	if err := m.db.QueryRowContext(ctx, query, scope.ScopeID, days, scope.ScopeID, scope.UserName).Scan(&rate.Draft, &rate.Active, &rate.Completed, &rate.Total); err != nil {
+		return nil, err
+	}
+	if rate.Total > 0 {
+		rate.Completion = float64(rate.Completed) / float64(rate.Total)
+	}
+	return rate, nil
+}
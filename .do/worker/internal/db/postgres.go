@@ -0,0 +1,1000 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/do-focus/worker/pkg/models"
+	_ "github.com/lib/pq"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// Postgres implements the Adapter interface for Postgres, storing the
+// observations.tags column as real JSONB via pqtype.NullRawMessage instead
+// of MySQL's stringified JSON column.
+type Postgres struct {
+	db *sql.DB
+}
+
+// NewPostgres creates a new Postgres adapter.
+func NewPostgres(cfg Config) (*Postgres, error) {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database)
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres: %w", err)
+	}
+
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	p := &Postgres{db: db}
+
+	if err := runMigrations(db, "postgres"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	return p, nil
+}
+
+// Health checks database connectivity.
+func (p *Postgres) Health(ctx context.Context) error {
+	return p.db.PingContext(ctx)
+}
+
+// Close closes the database connection.
+func (p *Postgres) Close() error {
+	return p.db.Close()
+}
+
+// CreateSession creates a new session.
+func (p *Postgres) CreateSession(ctx context.Context, scope ScopeContext, session *models.Session) error {
+	query := `
+		INSERT INTO sessions (id, scope_id, user_name, project_id, started_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+	`
+	_, err := p.db.ExecContext(ctx, query, session.ID, scope.ScopeID, session.UserName, session.ProjectID, session.StartedAt)
+	return err
+}
+
+// GetSession retrieves a session by ID within scope.
+func (p *Postgres) GetSession(ctx context.Context, scope ScopeContext, id string) (*models.Session, error) {
+	query := `
+		SELECT id, scope_id, user_name, COALESCE(project_id, ''), started_at, ended_at, COALESCE(summary, ''), created_at, updated_at
+		FROM sessions
+		WHERE id = $1 AND scope_id = $2
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $2 AND sm.user_name = $3)
+	`
+	session := &models.Session{}
+	err := p.db.QueryRowContext(ctx, query, id, scope.ScopeID, scope.UserName).Scan(
+		&session.ID, &session.ScopeID, &session.UserName, &session.ProjectID, &session.StartedAt, &session.EndedAt,
+		&session.Summary, &session.CreatedAt, &session.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return session, err
+}
+
+// GetLatestSession retrieves the latest session for the scoped user.
+func (p *Postgres) GetLatestSession(ctx context.Context, scope ScopeContext) (*models.Session, error) {
+	query := `
+		SELECT id, scope_id, user_name, started_at, ended_at, COALESCE(summary, ''), created_at, updated_at
+		FROM sessions
+		WHERE user_name = $1 AND scope_id = $2
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $2 AND sm.user_name = $1)
+		ORDER BY started_at DESC
+		LIMIT 1
+	`
+	session := &models.Session{}
+	err := p.db.QueryRowContext(ctx, query, scope.UserName, scope.ScopeID).Scan(
+		&session.ID, &session.ScopeID, &session.UserName, &session.StartedAt, &session.EndedAt,
+		&session.Summary, &session.CreatedAt, &session.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return session, err
+}
+
+// EndSession ends a session with an optional summary.
+func (p *Postgres) EndSession(ctx context.Context, scope ScopeContext, id string, summary string) error {
+	query := `
+		UPDATE sessions SET ended_at = NOW(), summary = $1
+		WHERE id = $2 AND scope_id = $3
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $3 AND sm.user_name = $4)
+	`
+	_, err := p.db.ExecContext(ctx, query, summary, id, scope.ScopeID, scope.UserName)
+	return err
+}
+
+// GetRecentSessions retrieves recent sessions within scope.
+func (p *Postgres) GetRecentSessions(ctx context.Context, scope ScopeContext, limit int) ([]models.Session, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	query := `
+		SELECT id, scope_id, user_name, started_at, ended_at, COALESCE(summary, ''), created_at, updated_at
+		FROM sessions
+		WHERE scope_id = $1
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $1 AND sm.user_name = $2)
+		ORDER BY started_at DESC
+		LIMIT $3
+	`
+	rows, err := p.db.QueryContext(ctx, query, scope.ScopeID, scope.UserName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []models.Session
+	for rows.Next() {
+		var session models.Session
+		if err := rows.Scan(&session.ID, &session.ScopeID, &session.UserName, &session.StartedAt, &session.EndedAt, &session.Summary, &session.CreatedAt, &session.UpdatedAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// ListSessionsAfter keyset-paginates sessions newest-first using the
+// (created_at, id) pair from cursor, which stays O(log n) as the table
+// grows since it hits the same index as the ORDER BY rather than scanning
+// and discarding OFFSET rows.
+func (p *Postgres) ListSessionsAfter(ctx context.Context, scope ScopeContext, cursor *Cursor, limit int) ([]models.Session, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	query := `
+		SELECT id, scope_id, user_name, started_at, ended_at, COALESCE(summary, ''), created_at, updated_at
+		FROM sessions
+		WHERE scope_id = $1
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $1 AND sm.user_name = $2)
+			AND ($3::timestamptz IS NULL OR (created_at, id) < ($3, $4))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $5
+	`
+	lastCreatedAt, lastID := cursorArgs(cursor)
+	rows, err := p.db.QueryContext(ctx, query, scope.ScopeID, scope.UserName, lastCreatedAt, lastID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []models.Session
+	for rows.Next() {
+		var session models.Session
+		if err := rows.Scan(&session.ID, &session.ScopeID, &session.UserName, &session.StartedAt, &session.EndedAt, &session.Summary, &session.CreatedAt, &session.UpdatedAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// CreateObservation creates a new observation, storing Tags as JSONB, and
+// upserts its tags into the normalized tag tables within the same
+// transaction. Scope membership is verified via the parent session, since
+// observations don't carry their own scope_id.
+func (p *Postgres) CreateObservation(ctx context.Context, scope ScopeContext, obs *models.Observation) error {
+	query := `
+		INSERT INTO observations (session_id, agent_name, type, content, importance, tags, created_at)
+		SELECT $1, $2, $3, $4, $5, $6, NOW()
+		FROM sessions s
+		WHERE s.id = $7 AND s.scope_id = $8
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $8 AND sm.user_name = $9)
+		RETURNING id
+	`
+	tags, err := tagsToJSONB(obs.Tags)
+	if err != nil {
+		return err
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRowContext(ctx, query,
+		obs.SessionID, obs.AgentName, obs.Type, obs.Content, obs.Importance, tags,
+		obs.SessionID, scope.ScopeID, scope.UserName,
+	).Scan(&obs.ID); err != nil {
+		return err
+	}
+
+	if err := upsertObservationTagsPostgres(ctx, tx, obs.ID, obs.Tags); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateObservationsBatch inserts observations in a single transaction,
+// mutating each element's ID on success. See MySQL's implementation for
+// why sizing the batch is left to the caller.
+func (p *Postgres) CreateObservationsBatch(ctx context.Context, scope ScopeContext, observations []*models.Observation) error {
+	if len(observations) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO observations (session_id, agent_name, type, content, importance, tags, created_at)
+		SELECT $1, $2, $3, $4, $5, $6, NOW()
+		FROM sessions s
+		WHERE s.id = $7 AND s.scope_id = $8
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $8 AND sm.user_name = $9)
+		RETURNING id
+	`
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, obs := range observations {
+		tags, err := tagsToJSONB(obs.Tags)
+		if err != nil {
+			return err
+		}
+		if err := tx.QueryRowContext(ctx, query,
+			obs.SessionID, obs.AgentName, obs.Type, obs.Content, obs.Importance, tags,
+			obs.SessionID, scope.ScopeID, scope.UserName,
+		).Scan(&obs.ID); err != nil {
+			return err
+		}
+		if err := upsertObservationTagsPostgres(ctx, tx, obs.ID, obs.Tags); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// upsertObservationTagsPostgres decodes the JSON tags blob and links each tag
+// to the observation, creating tag rows as needed. The JSONB column itself is
+// left untouched as a denormalized read cache.
+func upsertObservationTagsPostgres(ctx context.Context, tx *sql.Tx, observationID int64, tagsJSON string) error {
+	tags, err := parseTagsJSON(tagsJSON)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		var tagID int64
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO tags (name) VALUES ($1)
+			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		`, tag).Scan(&tagID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO observation_tags (observation_id, tag_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, observationID, tagID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetObservations retrieves observations for a session within scope.
+func (p *Postgres) GetObservations(ctx context.Context, scope ScopeContext, sessionID string) ([]models.Observation, error) {
+	query := `
+		SELECT o.id, o.session_id, COALESCE(o.agent_name, ''), o.type, o.content, o.importance, o.tags, o.created_at
+		FROM observations o
+		JOIN sessions s ON o.session_id = s.id
+		WHERE o.session_id = $1 AND s.scope_id = $2
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $2 AND sm.user_name = $3)
+		ORDER BY o.created_at DESC
+	`
+	return p.scanObservations(ctx, query, sessionID, scope.ScopeID, scope.UserName)
+}
+
+// GetRecentObservations retrieves recent observations across sessions within scope.
+func (p *Postgres) GetRecentObservations(ctx context.Context, scope ScopeContext, limit int) ([]models.Observation, error) {
+	query := `
+		SELECT o.id, o.session_id, COALESCE(o.agent_name, ''), o.type, o.content, o.importance, o.tags, o.created_at
+		FROM observations o
+		JOIN sessions s ON o.session_id = s.id
+		WHERE s.scope_id = $1
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $1 AND sm.user_name = $2)
+		ORDER BY o.importance DESC, o.created_at DESC
+		LIMIT $3
+	`
+	return p.scanObservations(ctx, query, scope.ScopeID, scope.UserName, limit)
+}
+
+// GetObservationsFiltered retrieves observations with optional filters, within scope.
+func (p *Postgres) GetObservationsFiltered(ctx context.Context, scope ScopeContext, sessionID string, obsType string, limit int) ([]models.Observation, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	query := `
+		SELECT o.id, o.session_id, COALESCE(o.agent_name, ''), o.type, o.content, o.importance, o.tags, o.created_at
+		FROM observations o
+		JOIN sessions s ON o.session_id = s.id
+		WHERE ($1 = '' OR o.session_id = $1) AND ($2 = '' OR o.type = $2)
+			AND s.scope_id = $3
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $3 AND sm.user_name = $4)
+		ORDER BY o.created_at DESC
+		LIMIT $5
+	`
+	return p.scanObservations(ctx, query, sessionID, obsType, scope.ScopeID, scope.UserName, limit)
+}
+
+// ListObservationsAfter keyset-paginates observations newest-first, honoring
+// the same sessionID/obsType filters as GetObservationsFiltered.
+func (p *Postgres) ListObservationsAfter(ctx context.Context, scope ScopeContext, sessionID string, obsType string, cursor *Cursor, limit int) ([]models.Observation, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	query := `
+		SELECT o.id, o.session_id, COALESCE(o.agent_name, ''), o.type, o.content, o.importance, o.tags, o.created_at
+		FROM observations o
+		JOIN sessions s ON o.session_id = s.id
+		WHERE ($1 = '' OR o.session_id = $1) AND ($2 = '' OR o.type = $2)
+			AND s.scope_id = $3
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $3 AND sm.user_name = $4)
+			AND ($5::timestamptz IS NULL OR (o.created_at, o.id) < ($5, $6::bigint))
+		ORDER BY o.created_at DESC, o.id DESC
+		LIMIT $7
+	`
+	lastCreatedAt, lastID := cursorArgs(cursor)
+	return p.scanObservations(ctx, query, sessionID, obsType, scope.ScopeID, scope.UserName, lastCreatedAt, lastID, limit)
+}
+
+// SearchObservations searches observations by full-text relevance within scope.
+func (p *Postgres) SearchObservations(ctx context.Context, scope ScopeContext, query string, limit int) ([]models.Observation, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	sqlQuery := `
+		SELECT o.id, o.session_id, COALESCE(o.agent_name, ''), o.type, o.content, o.importance, o.tags, o.created_at
+		FROM observations o
+		JOIN sessions s ON o.session_id = s.id
+		WHERE o.content_tsv @@ plainto_tsquery('english', $1)
+			AND s.scope_id = $2
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $2 AND sm.user_name = $3)
+		ORDER BY ts_rank(o.content_tsv, plainto_tsquery('english', $1)) DESC
+		LIMIT $4
+	`
+	return p.scanObservations(ctx, sqlQuery, query, scope.ScopeID, scope.UserName, limit)
+}
+
+// SearchObservationsRanked runs a full-text search with optional filters and
+// returns hits ordered by a blended relevance/importance/recency score,
+// mirroring MySQL.SearchObservationsRanked.
+func (p *Postgres) SearchObservationsRanked(ctx context.Context, scope ScopeContext, query string, filters SearchFilters, limit int) ([]models.ObservationHit, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	toTSQuery := "plainto_tsquery"
+	if filters.Mode == SearchBoolean {
+		toTSQuery = "websearch_to_tsquery"
+	}
+
+	args := []interface{}{query, scope.ScopeID, scope.UserName}
+	conditions := []string{
+		fmt.Sprintf("o.content_tsv @@ %s('english', $1)", toTSQuery),
+		"s.scope_id = $2",
+		"EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $2 AND sm.user_name = $3)",
+	}
+	if filters.UserName != "" {
+		args = append(args, filters.UserName)
+		conditions = append(conditions, fmt.Sprintf("s.user_name = $%d", len(args)))
+	}
+	if filters.SessionID != "" {
+		args = append(args, filters.SessionID)
+		conditions = append(conditions, fmt.Sprintf("o.session_id = $%d", len(args)))
+	}
+	if filters.Type != "" {
+		args = append(args, filters.Type)
+		conditions = append(conditions, fmt.Sprintf("o.type = $%d", len(args)))
+	}
+	if filters.MinImportance > 0 {
+		args = append(args, filters.MinImportance)
+		conditions = append(conditions, fmt.Sprintf("o.importance >= $%d", len(args)))
+	}
+	if filters.From != nil {
+		args = append(args, *filters.From)
+		conditions = append(conditions, fmt.Sprintf("o.created_at >= $%d", len(args)))
+	}
+	if filters.To != nil {
+		args = append(args, *filters.To)
+		conditions = append(conditions, fmt.Sprintf("o.created_at <= $%d", len(args)))
+	}
+	args = append(args, limit)
+	limitPlaceholder := fmt.Sprintf("$%d", len(args))
+
+	sqlQuery := fmt.Sprintf(`
+		SELECT o.id, o.session_id, COALESCE(o.agent_name, ''), o.type, o.content, o.importance, o.tags, o.created_at,
+			ts_rank(o.content_tsv, %s('english', $1)) AS relevance
+		FROM observations o
+		JOIN sessions s ON o.session_id = s.id
+		WHERE %s
+		ORDER BY relevance DESC
+		LIMIT %s
+	`, toTSQuery, strings.Join(conditions, " AND "), limitPlaceholder)
+
+	rows, err := p.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []models.ObservationHit
+	for rows.Next() {
+		var obs models.Observation
+		var tags pqtype.NullRawMessage
+		var relevance float64
+		if err := rows.Scan(&obs.ID, &obs.SessionID, &obs.AgentName, &obs.Type, &obs.Content, &obs.Importance, &tags, &obs.CreatedAt, &relevance); err != nil {
+			return nil, err
+		}
+		obs.Tags = jsonbToTags(tags)
+		hits = append(hits, models.ObservationHit{
+			Observation: obs,
+			Score:       combinedSearchScore(relevance, obs.Importance, obs.CreatedAt),
+		})
+	}
+	return hits, rows.Err()
+}
+
+// cursorArgs returns the (created_at, id) pair to bind into a keyset WHERE
+// clause, as untyped nils when cursor is nil so the "$1::timestamptz IS
+// NULL OR ..." guard in the query takes the first page unfiltered.
+func cursorArgs(cursor *Cursor) (interface{}, interface{}) {
+	if cursor == nil {
+		return nil, nil
+	}
+	return cursor.LastCreatedAt, cursor.LastID
+}
+
+// scanObservations runs query with args and scans the standard observation
+// column set, converting the JSONB tags column back to the string form
+// models.Observation.Tags expects.
+func (p *Postgres) scanObservations(ctx context.Context, query string, args ...interface{}) ([]models.Observation, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var observations []models.Observation
+	for rows.Next() {
+		var obs models.Observation
+		var tags pqtype.NullRawMessage
+		if err := rows.Scan(&obs.ID, &obs.SessionID, &obs.AgentName, &obs.Type, &obs.Content, &obs.Importance, &tags, &obs.CreatedAt); err != nil {
+			return nil, err
+		}
+		obs.Tags = jsonbToTags(tags)
+		observations = append(observations, obs)
+	}
+	return observations, rows.Err()
+}
+
+// CreateSummary creates a new summary within scope.
+func (p *Postgres) CreateSummary(ctx context.Context, scope ScopeContext, summary *models.Summary) error {
+	mode := summary.Mode
+	if mode == "" {
+		mode = "rule"
+	}
+	query := `
+		INSERT INTO summaries (scope_id, session_id, type, content, mode, provider, prompt_tokens, completion_tokens, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW()) RETURNING id
+	`
+	err := p.db.QueryRowContext(ctx, query, scope.ScopeID, summary.SessionID, summary.Type, summary.Content,
+		mode, nullString(summary.Provider), summary.PromptTokens, summary.CompletionTokens).Scan(&summary.ID)
+	if err != nil {
+		return err
+	}
+	summary.Mode = mode
+	return nil
+}
+
+// GetSummaries retrieves summaries by type within scope.
+func (p *Postgres) GetSummaries(ctx context.Context, scope ScopeContext, summaryType string, limit int) ([]models.Summary, error) {
+	query := `
+		SELECT id, scope_id, COALESCE(session_id, ''), type, content, mode, provider, prompt_tokens, completion_tokens, created_at
+		FROM summaries
+		WHERE type = $1 AND scope_id = $2
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $2 AND sm.user_name = $3)
+		ORDER BY created_at DESC
+		LIMIT $4
+	`
+	rows, err := p.db.QueryContext(ctx, query, summaryType, scope.ScopeID, scope.UserName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []models.Summary
+	for rows.Next() {
+		var sum models.Summary
+		var provider sql.NullString
+		if err := rows.Scan(&sum.ID, &sum.ScopeID, &sum.SessionID, &sum.Type, &sum.Content, &sum.Mode, &provider, &sum.PromptTokens, &sum.CompletionTokens, &sum.CreatedAt); err != nil {
+			return nil, err
+		}
+		sum.Provider = provider.String
+		summaries = append(summaries, sum)
+	}
+	return summaries, rows.Err()
+}
+
+// GetAllSummaries retrieves all summaries within a date range and scope.
+func (p *Postgres) GetAllSummaries(ctx context.Context, scope ScopeContext, days int, limit int) ([]models.Summary, error) {
+	if days <= 0 {
+		days = 7
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	query := `
+		SELECT id, scope_id, COALESCE(session_id, ''), type, content, mode, provider, prompt_tokens, completion_tokens, created_at
+		FROM summaries
+		WHERE created_at >= NOW() - ($1 || ' days')::INTERVAL AND scope_id = $2
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $2 AND sm.user_name = $3)
+		ORDER BY created_at DESC
+		LIMIT $4
+	`
+	rows, err := p.db.QueryContext(ctx, query, days, scope.ScopeID, scope.UserName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []models.Summary
+	for rows.Next() {
+		var sum models.Summary
+		var provider sql.NullString
+		if err := rows.Scan(&sum.ID, &sum.ScopeID, &sum.SessionID, &sum.Type, &sum.Content, &sum.Mode, &provider, &sum.PromptTokens, &sum.CompletionTokens, &sum.CreatedAt); err != nil {
+			return nil, err
+		}
+		sum.Provider = provider.String
+		summaries = append(summaries, sum)
+	}
+	return summaries, rows.Err()
+}
+
+// ListSummariesAfter keyset-paginates summaries from the last `days` days,
+// newest-first.
+func (p *Postgres) ListSummariesAfter(ctx context.Context, scope ScopeContext, days int, cursor *Cursor, limit int) ([]models.Summary, error) {
+	if days <= 0 {
+		days = 7
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	query := `
+		SELECT id, scope_id, COALESCE(session_id, ''), type, content, mode, provider, prompt_tokens, completion_tokens, created_at
+		FROM summaries
+		WHERE created_at >= NOW() - ($1 || ' days')::INTERVAL AND scope_id = $2
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $2 AND sm.user_name = $3)
+			AND ($4::timestamptz IS NULL OR (created_at, id) < ($4, $5::bigint))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $6
+	`
+	lastCreatedAt, lastID := cursorArgs(cursor)
+	rows, err := p.db.QueryContext(ctx, query, days, scope.ScopeID, scope.UserName, lastCreatedAt, lastID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []models.Summary
+	for rows.Next() {
+		var sum models.Summary
+		var provider sql.NullString
+		if err := rows.Scan(&sum.ID, &sum.ScopeID, &sum.SessionID, &sum.Type, &sum.Content, &sum.Mode, &provider, &sum.PromptTokens, &sum.CompletionTokens, &sum.CreatedAt); err != nil {
+			return nil, err
+		}
+		sum.Provider = provider.String
+		summaries = append(summaries, sum)
+	}
+	return summaries, rows.Err()
+}
+
+// CreatePlan creates a new plan within scope.
+func (p *Postgres) CreatePlan(ctx context.Context, scope ScopeContext, plan *models.Plan) error {
+	query := `
+		INSERT INTO plans (scope_id, session_id, title, content, status, file_path, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 'draft', $5, NOW(), NOW())
+		RETURNING id
+	`
+	return p.db.QueryRowContext(ctx, query, scope.ScopeID, plan.SessionID, plan.Title, plan.Content, plan.FilePath).Scan(&plan.ID)
+}
+
+// GetActivePlan retrieves the active plan within scope.
+func (p *Postgres) GetActivePlan(ctx context.Context, scope ScopeContext) (*models.Plan, error) {
+	query := `
+		SELECT id, scope_id, session_id, title, content, status, COALESCE(file_path, ''), created_at, updated_at
+		FROM plans
+		WHERE scope_id = $1 AND status = 'active'
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $1 AND sm.user_name = $2)
+		ORDER BY updated_at DESC
+		LIMIT 1
+	`
+	plan := &models.Plan{}
+	err := p.db.QueryRowContext(ctx, query, scope.ScopeID, scope.UserName).Scan(
+		&plan.ID, &plan.ScopeID, &plan.SessionID, &plan.Title, &plan.Content,
+		&plan.Status, &plan.FilePath, &plan.CreatedAt, &plan.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return plan, err
+}
+
+// GetAllPlans retrieves all plans with optional session filter, within scope.
+func (p *Postgres) GetAllPlans(ctx context.Context, scope ScopeContext, sessionID string, limit int) ([]models.Plan, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	query := `
+		SELECT id, COALESCE(session_id, ''), title, content, status, COALESCE(file_path, ''), created_at, updated_at
+		FROM plans
+		WHERE ($1 = '' OR session_id = $1) AND scope_id = $2
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $2 AND sm.user_name = $3)
+		ORDER BY updated_at DESC
+		LIMIT $4
+	`
+	rows, err := p.db.QueryContext(ctx, query, sessionID, scope.ScopeID, scope.UserName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plans []models.Plan
+	for rows.Next() {
+		var plan models.Plan
+		if err := rows.Scan(&plan.ID, &plan.SessionID, &plan.Title, &plan.Content, &plan.Status, &plan.FilePath, &plan.CreatedAt, &plan.UpdatedAt); err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, rows.Err()
+}
+
+// ListPlansAfter keyset-paginates plans newest-first by created_at,
+// optionally scoped to sessionID.
+func (p *Postgres) ListPlansAfter(ctx context.Context, scope ScopeContext, sessionID string, cursor *Cursor, limit int) ([]models.Plan, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	query := `
+		SELECT id, COALESCE(session_id, ''), title, content, status, COALESCE(file_path, ''), created_at, updated_at
+		FROM plans
+		WHERE ($1 = '' OR session_id = $1) AND scope_id = $2
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $2 AND sm.user_name = $3)
+			AND ($4::timestamptz IS NULL OR (created_at, id) < ($4, $5::bigint))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $6
+	`
+	lastCreatedAt, lastID := cursorArgs(cursor)
+	rows, err := p.db.QueryContext(ctx, query, sessionID, scope.ScopeID, scope.UserName, lastCreatedAt, lastID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plans []models.Plan
+	for rows.Next() {
+		var plan models.Plan
+		if err := rows.Scan(&plan.ID, &plan.SessionID, &plan.Title, &plan.Content, &plan.Status, &plan.FilePath, &plan.CreatedAt, &plan.UpdatedAt); err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, rows.Err()
+}
+
+// UpdatePlanStatus updates a plan's status within scope.
+func (p *Postgres) UpdatePlanStatus(ctx context.Context, scope ScopeContext, id int64, status string) error {
+	query := `
+		UPDATE plans SET status = $1
+		WHERE id = $2 AND scope_id = $3
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $3 AND sm.user_name = $4)
+	`
+	_, err := p.db.ExecContext(ctx, query, status, id, scope.ScopeID, scope.UserName)
+	return err
+}
+
+// GetTeamContext retrieves context from other members of the scope.
+func (p *Postgres) GetTeamContext(ctx context.Context, scope ScopeContext) ([]models.TeamContext, error) {
+	query := `
+		SELECT
+			s.user_name,
+			MAX(s.started_at) as last_activity,
+			COALESCE(MAX(s.summary), '') as summary,
+			COALESCE(MAX(pl.title), '') as active_plan
+		FROM sessions s
+		LEFT JOIN plans pl ON pl.session_id = s.id AND pl.status = 'active'
+		WHERE s.scope_id = $1 AND s.user_name != $2 AND s.ended_at IS NOT NULL
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $1 AND sm.user_name = $2)
+		GROUP BY s.user_name
+		ORDER BY last_activity DESC
+		LIMIT 10
+	`
+	rows, err := p.db.QueryContext(ctx, query, scope.ScopeID, scope.UserName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contexts []models.TeamContext
+	for rows.Next() {
+		var tc models.TeamContext
+		if err := rows.Scan(&tc.UserName, &tc.LastActivity, &tc.Summary, &tc.ActivePlan); err != nil {
+			return nil, err
+		}
+		contexts = append(contexts, tc)
+	}
+	return contexts, rows.Err()
+}
+
+// GetProjects retrieves all registered projects within scope with session statistics.
+func (p *Postgres) GetProjects(ctx context.Context, scope ScopeContext) ([]models.Project, error) {
+	query := `
+		SELECT
+			project_id,
+			project_id as path,
+			COUNT(*) as session_count,
+			MAX(started_at) as last_activity
+		FROM sessions
+		WHERE project_id IS NOT NULL AND project_id != '' AND scope_id = $1
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $1 AND sm.user_name = $2)
+		GROUP BY project_id
+		ORDER BY last_activity DESC
+	`
+	rows, err := p.db.QueryContext(ctx, query, scope.ScopeID, scope.UserName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []models.Project
+	for rows.Next() {
+		var pr models.Project
+		if err := rows.Scan(&pr.ID, &pr.Path, &pr.SessionCount, &pr.LastActivity); err != nil {
+			return nil, err
+		}
+		projects = append(projects, pr)
+	}
+	return projects, rows.Err()
+}
+
+// CreateScope creates a new scope and registers its owner as the first member.
+func (p *Postgres) CreateScope(ctx context.Context, name string, ownerUser string) (*models.Scope, error) {
+	id := fmt.Sprintf("scope-%s-%d", strings.ToLower(strings.ReplaceAll(name, " ", "-")), time.Now().UnixNano())
+	if _, err := p.db.ExecContext(ctx, `INSERT INTO scopes (id, name, owner_user, created_at) VALUES ($1, $2, $3, NOW())`, id, name, ownerUser); err != nil {
+		return nil, err
+	}
+	if _, err := p.db.ExecContext(ctx, `INSERT INTO scope_members (scope_id, user_name, role, created_at) VALUES ($1, $2, 'owner', NOW())`, id, ownerUser); err != nil {
+		return nil, err
+	}
+	return &models.Scope{ID: id, Name: name, OwnerUser: ownerUser, CreatedAt: time.Now()}, nil
+}
+
+// AddScopeMember adds or updates a user's membership and role within a scope.
+func (p *Postgres) AddScopeMember(ctx context.Context, scopeID string, userName string, role string) error {
+	query := `
+		INSERT INTO scope_members (scope_id, user_name, role, created_at) VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (scope_id, user_name) DO UPDATE SET role = EXCLUDED.role
+	`
+	_, err := p.db.ExecContext(ctx, query, scopeID, userName, role)
+	return err
+}
+
+// GetScopeMemberRole returns userName's role within scopeID, or "" if they
+// aren't a member of it.
+func (p *Postgres) GetScopeMemberRole(ctx context.Context, scopeID string, userName string) (string, error) {
+	var role string
+	err := p.db.QueryRowContext(ctx,
+		`SELECT role FROM scope_members WHERE scope_id = $1 AND user_name = $2`,
+		scopeID, userName,
+	).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return role, nil
+}
+
+// EnsureDefaultScope idempotently provisions userName's default scope and
+// owner membership in it, for users who signed up after the scopes
+// migration's one-time backfill ran.
+func (p *Postgres) EnsureDefaultScope(ctx context.Context, userName string) error {
+	scopeID := "default-" + userName
+	if _, err := p.db.ExecContext(ctx,
+		`INSERT INTO scopes (id, name, owner_user, created_at) VALUES ($1, $2, $3, NOW()) ON CONFLICT (id) DO NOTHING`,
+		scopeID, userName+"'s workspace", userName,
+	); err != nil {
+		return err
+	}
+	_, err := p.db.ExecContext(ctx,
+		`INSERT INTO scope_members (scope_id, user_name, role, created_at) VALUES ($1, $2, 'owner', NOW()) ON CONFLICT (scope_id, user_name) DO NOTHING`,
+		scopeID, userName,
+	)
+	return err
+}
+
+// ListUserScopes lists every scope a user belongs to, ordered by creation.
+func (p *Postgres) ListUserScopes(ctx context.Context, userName string) ([]models.Scope, error) {
+	query := `
+		SELECT s.id, s.name, s.owner_user, s.created_at
+		FROM scopes s
+		JOIN scope_members sm ON sm.scope_id = s.id
+		WHERE sm.user_name = $1
+		ORDER BY s.created_at ASC
+	`
+	rows, err := p.db.QueryContext(ctx, query, userName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scopes []models.Scope
+	for rows.Next() {
+		var sc models.Scope
+		if err := rows.Scan(&sc.ID, &sc.Name, &sc.OwnerUser, &sc.CreatedAt); err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, sc)
+	}
+	return scopes, rows.Err()
+}
+
+// AuthenticateToken resolves a bearer token to the user and scopes it
+// grants by looking up its SHA-256 hash in api_tokens, and best-effort
+// records the token's last use.
+func (p *Postgres) AuthenticateToken(ctx context.Context, token string) (*TokenInfo, error) {
+	hash := hashAPIToken(token)
+	var userName, scopes string
+	err := p.db.QueryRowContext(ctx, `
+		SELECT user_name, scopes FROM api_tokens WHERE token_hash = $1
+	`, hash).Scan(&userName, &scopes)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, _ = p.db.ExecContext(ctx, `UPDATE api_tokens SET last_used_at = now() WHERE token_hash = $1`, hash)
+	return &TokenInfo{UserName: userName, Scopes: strings.Split(scopes, ",")}, nil
+}
+
+// GetObservationsByTags retrieves observations tagged with any (or, with
+// matchAll, all) of tags within scope, most recent first.
+func (p *Postgres) GetObservationsByTags(ctx context.Context, scope ScopeContext, tags []string, matchAll bool, limit int) ([]models.Observation, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	args := []interface{}{scope.ScopeID, scope.UserName}
+	placeholders := make([]string, len(tags))
+	for i, t := range tags {
+		args = append(args, t)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	args = append(args, limit)
+	limitPlaceholder := fmt.Sprintf("$%d", len(args))
+
+	having := ""
+	if matchAll {
+		having = fmt.Sprintf("HAVING COUNT(DISTINCT t.name) = %d", len(tags))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT o.id, o.session_id, COALESCE(o.agent_name, ''), o.type, o.content, o.importance, o.tags, o.created_at
+		FROM observations o
+		JOIN sessions s ON o.session_id = s.id
+		JOIN observation_tags ot ON ot.observation_id = o.id
+		JOIN tags t ON t.id = ot.tag_id
+		WHERE s.scope_id = $1
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $1 AND sm.user_name = $2)
+			AND t.name IN (%s)
+		GROUP BY o.id
+		%s
+		ORDER BY o.created_at DESC
+		LIMIT %s
+	`, strings.Join(placeholders, ","), having, limitPlaceholder)
+
+	return p.scanObservations(ctx, query, args...)
+}
+
+// ListTags returns the distinct tags used within scope, most-used first.
+func (p *Postgres) ListTags(ctx context.Context, scope ScopeContext, prefix string, limit int) ([]models.TagCount, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	query := `
+		SELECT t.name, COUNT(DISTINCT ot.observation_id) AS count
+		FROM tags t
+		JOIN observation_tags ot ON ot.tag_id = t.id
+		JOIN observations o ON o.id = ot.observation_id
+		JOIN sessions s ON o.session_id = s.id
+		WHERE s.scope_id = $1 AND ($2 = '' OR t.name LIKE $2 || '%')
+			AND EXISTS (SELECT 1 FROM scope_members sm WHERE sm.scope_id = $1 AND sm.user_name = $3)
+		GROUP BY t.name
+		ORDER BY count DESC
+		LIMIT $4
+	`
+	rows, err := p.db.QueryContext(ctx, query, scope.ScopeID, prefix, scope.UserName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []models.TagCount
+	for rows.Next() {
+		var t models.TagCount
+		if err := rows.Scan(&t.Name, &t.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// RenameTag renames a tag across every observation that references it, but
+// only if scope is a member of a scope that actually uses oldName — tags
+// are a shared global table, so without this check any token holder could
+// rename a tag belonging to another tenant's observations.
+func (p *Postgres) RenameTag(ctx context.Context, scope ScopeContext, oldName string, newName string) error {
+	result, err := p.db.ExecContext(ctx, `
+		UPDATE tags SET name = $1
+		WHERE name = $2
+			AND EXISTS (
+				SELECT 1
+				FROM observation_tags ot
+				JOIN observations o ON o.id = ot.observation_id
+				JOIN sessions s ON s.id = o.session_id
+				JOIN scope_members sm ON sm.scope_id = s.scope_id
+				WHERE ot.tag_id = tags.id AND s.scope_id = $3 AND sm.user_name = $4
+			)
+	`, newName, oldName, scope.ScopeID, scope.UserName)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// tagsToJSONB converts the string-encoded JSON array stored on
+// models.Observation.Tags into a pqtype.NullRawMessage for JSONB columns.
+func tagsToJSONB(tags string) (pqtype.NullRawMessage, error) {
+	if tags == "" {
+		return pqtype.NullRawMessage{}, nil
+	}
+	return pqtype.NullRawMessage{RawMessage: []byte(tags), Valid: true}, nil
+}
+
+// jsonbToTags converts a JSONB tags column back into the string form
+// models.Observation.Tags expects.
+func jsonbToTags(tags pqtype.NullRawMessage) string {
+	if !tags.Valid {
+		return ""
+	}
+	return string(tags.RawMessage)
+}
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"math"
+	"time"
+)
+
+// searchRecencyHalfLife is the half-life used to decay an observation's
+// recency contribution to its combined search score.
+const searchRecencyHalfLife = 72 * time.Hour
+
+// combinedSearchScore blends full-text relevance with importance and
+// recency so that recent, high-importance observations outrank older,
+// lower-importance ones even at similar relevance.
+func combinedSearchScore(relevance float64, importance int, createdAt time.Time) float64 {
+	recency := recencyDecay(createdAt)
+	importanceScore := float64(importance) / 5
+	return relevance*0.6 + importanceScore*0.2 + recency*0.2
+}
+
+// recencyDecay returns a value in (0, 1] that halves every
+// searchRecencyHalfLife since createdAt.
+func recencyDecay(createdAt time.Time) float64 {
+	age := time.Since(createdAt)
+	if age < 0 {
+		age = 0
+	}
+	return math.Exp(-math.Ln2 * age.Hours() / searchRecencyHalfLife.Hours())
+}
@@ -0,0 +1,17 @@
+package db
+
+import "encoding/json"
+
+// parseTagsJSON decodes the JSON array string stored in
+// models.Observation.Tags into plain tag names. An empty string yields no
+// tags; this is the inverse of mysqlTagsToJSON.
+func parseTagsJSON(tagsJSON string) ([]string, error) {
+	if tagsJSON == "" {
+		return nil, nil
+	}
+	var tags []string
+	if err := json.Unmarshal([]byte(tagsJSON), &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/do-focus/worker/internal/db"
+	"github.com/do-focus/worker/pkg/models"
+	"github.com/gin-gonic/gin"
+)
+
+// authMiddleware resolves the bearer token on every request to the user
+// and scopes it grants, rejecting with 401 when the Authorization header
+// is missing or the token is unknown. On success it stashes the resolved
+// token in the context for rateLimitMiddleware and requestContextMiddleware.
+func (s *Server) authMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token := strings.TrimPrefix(header, "Bearer ")
+		if token == "" || token == header {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{
+				Error:   "unauthorized",
+				Message: "missing or malformed Authorization header",
+			})
+			return
+		}
+
+		info, err := s.db.AuthenticateToken(c.Request.Context(), token)
+		if err != nil {
+			recordDBError(c.FullPath())
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "database_error",
+				Message: err.Error(),
+			})
+			return
+		}
+		if info == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{
+				Error:   "unauthorized",
+				Message: "unknown or revoked API token",
+			})
+			return
+		}
+
+		c.Set("token", info)
+		c.Next()
+	}
+}
+
+// requireScope aborts with 403 unless the authenticated token (set by
+// authMiddleware) grants scope. Use it on handlers that read across users,
+// where the requesting user's own read/write scopes aren't enough.
+func requireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := c.MustGet("token").(*db.TokenInfo)
+		if !info.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "forbidden",
+				Message: "token lacks required scope: " + scope,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// requestContextMiddleware resolves the effective user from the token
+// authMiddleware attached to the context, so handlers read it via
+// c.MustGet("user").(string) instead of the old c.Query("user")/
+// DO_USER_NAME fallback chain. When the request doesn't name an explicit
+// scope_id, it also provisions the user's default scope: the scopes
+// migration only backfilled that for users who already had sessions at
+// migration time, so without this every scope-gated query a new user
+// makes would fail with no matching scope_members row.
+func (s *Server) requestContextMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := c.MustGet("token").(*db.TokenInfo)
+		c.Set("user", info.UserName)
+
+		if c.Query("scope_id") == "" {
+			if err := s.db.EnsureDefaultScope(c.Request.Context(), info.UserName); err != nil {
+				recordDBError(c.FullPath())
+				c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   "database_error",
+					Message: err.Error(),
+				})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimiter enforces a per-user token-bucket rate limit, refilled at
+// limit requests per minute. Buckets are created lazily per user and
+// never evicted; the worker serves a small, bounded set of local users,
+// so the memory a stale bucket holds isn't worth a cleanup goroutine.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	limit   float64
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastFill time.Time
+}
+
+// newRateLimiter builds a rateLimiter honoring DO_RATE_LIMIT (requests per
+// minute per user), defaulting to 60.
+func newRateLimiter() *rateLimiter {
+	limit := 60
+	if v := os.Getenv("DO_RATE_LIMIT"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	return &rateLimiter{buckets: make(map[string]*tokenBucket), limit: float64(limit)}
+}
+
+// allow reports whether user may make another request right now, consuming
+// one token from their bucket if so.
+func (r *rateLimiter) allow(user string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	b, ok := r.buckets[user]
+	if !ok {
+		b = &tokenBucket{tokens: r.limit, lastFill: now}
+		r.buckets[user] = b
+	} else {
+		elapsed := now.Sub(b.lastFill).Seconds()
+		b.tokens += elapsed * r.limit / 60
+		if b.tokens > r.limit {
+			b.tokens = r.limit
+		}
+		b.lastFill = now
+	}
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// rateLimitMiddleware throttles each authenticated user to limiter's
+// configured requests/minute, responding 429 with a Retry-After header
+// once exhausted.
+func rateLimitMiddleware(limiter *rateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := c.MustGet("token").(*db.TokenInfo)
+		if !limiter.allow(info.UserName) {
+			c.Header("Retry-After", "60")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, models.ErrorResponse{
+				Error:   "rate_limited",
+				Message: "too many requests, try again later",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types published through EventHub and streamed by handleContextStream.
+const (
+	EventObservationCreated = "observation.created"
+	EventSessionStarted     = "session.started"
+	EventSessionEnded       = "session.ended"
+	EventPlanUpdated        = "plan.updated"
+	EventPromptCreated      = "prompt.created"
+)
+
+// Event is a pub/sub message broadcast to SSE subscribers of
+// /api/context/stream.
+type Event struct {
+	Type      string      `json:"type"`
+	ScopeID   string      `json:"scope_id"`
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// EventHub is an in-process pub/sub hub that fans Events out to connected
+// SSE clients, scoped by user, so the mutating handlers can Publish without
+// knowing whether anyone is listening.
+type EventHub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan Event]struct{}
+}
+
+// NewEventHub creates an empty hub.
+func NewEventHub() *EventHub {
+	return &EventHub{subs: make(map[string]map[chan Event]struct{})}
+}
+
+// Subscribe registers a new listener for user's events, returning a channel
+// of future events and a cancel func to unregister and close it. The
+// channel is buffered so a slow reader can't block Publish.
+func (h *EventHub) Subscribe(user string) (<-chan Event, func()) {
+	ch := make(chan Event, 32)
+
+	h.mu.Lock()
+	if h.subs[user] == nil {
+		h.subs[user] = make(map[chan Event]struct{})
+	}
+	h.subs[user][ch] = struct{}{}
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[user][ch]; !ok {
+			return
+		}
+		delete(h.subs[user], ch)
+		if len(h.subs[user]) == 0 {
+			delete(h.subs, user)
+		}
+		close(ch)
+	}
+	return ch, cancel
+}
+
+// Publish fans ev out to every subscriber registered for user. Subscribers
+// too far behind to keep up are skipped rather than blocking the publisher.
+func (h *EventHub) Publish(user string, ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs[user] {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
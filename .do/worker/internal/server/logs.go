@@ -0,0 +1,185 @@
+package server
+
+import (
+	"bufio"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// workerLogPath returns the path the worker appends its own stdout/stderr
+// to, overridable via DO_WORKER_LOG_PATH for tests or alternate layouts.
+func workerLogPath() string {
+	if p := os.Getenv("DO_WORKER_LOG_PATH"); p != "" {
+		return p
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".do", "logs", "worker.log")
+}
+
+// handleLogs streams the worker's log file, letting the CLI subscribe to a
+// live tail over HTTP/unix instead of polling the file directly. Supported
+// query params: tail (int, default 200), follow (bool), grep (regexp).
+// Falls back to a 404 if the log file doesn't exist yet so the CLI can fall
+// back to its own file-tailing path.
+func (s *Server) handleLogs(c *gin.Context) {
+	path := workerLogPath()
+	f, err := os.Open(path)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "log file not found"})
+		return
+	}
+	defer f.Close()
+
+	var matcher *regexp.Regexp
+	if pattern := c.Query("grep"); pattern != "" {
+		matcher, err = regexp.Compile(pattern)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid grep pattern: " + err.Error()})
+			return
+		}
+	}
+
+	tail := 200
+	if v := c.Query("tail"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			tail = n
+		}
+	}
+
+	lines, err := tailLines(f, tail)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	flusher, canFlush := c.Writer.(http.Flusher)
+
+	writeLine := func(line string) {
+		if matcher != nil && !matcher.MatchString(line) {
+			return
+		}
+		c.Writer.Write([]byte(line + "\n"))
+	}
+	for _, line := range lines {
+		writeLine(line)
+	}
+	if canFlush {
+		flusher.Flush()
+	}
+
+	if c.Query("follow") != "1" && c.Query("follow") != "true" {
+		return
+	}
+
+	// Poll for new bytes appended to the file, re-opening on rotation
+	// (size shrinks or the file is replaced under us).
+	offset, _ := f.Seek(0, os.SEEK_CUR)
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if info.Size() < offset {
+				// Rotated or truncated; start over from the beginning.
+				f.Close()
+				f, err = os.Open(path)
+				if err != nil {
+					continue
+				}
+				offset = 0
+			}
+			if info.Size() == offset {
+				continue
+			}
+
+			f.Seek(offset, os.SEEK_SET)
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				writeLine(scanner.Text())
+			}
+			offset, _ = f.Seek(0, os.SEEK_CUR)
+			if canFlush {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// tailLines returns the last n lines of f by seeking from the end and
+// scanning backward for newlines, rather than reading the whole file.
+func tailLines(f *os.File, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+
+	const chunkSize = 4096
+	var (
+		pos       = size
+		newlines  = 0
+		buf       = make([]byte, chunkSize)
+		collected []byte
+	)
+
+	for pos > 0 && newlines <= n {
+		readSize := int64(chunkSize)
+		if pos < readSize {
+			readSize = pos
+		}
+		pos -= readSize
+
+		if _, err := f.ReadAt(buf[:readSize], pos); err != nil {
+			return nil, err
+		}
+		chunk := make([]byte, readSize, readSize+int64(len(collected)))
+		copy(chunk, buf[:readSize])
+		collected = append(chunk, collected...)
+
+		for _, b := range buf[:readSize] {
+			if b == '\n' {
+				newlines++
+			}
+		}
+	}
+
+	lines := splitLines(string(collected))
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
+func splitLines(s string) []string {
+	var lines []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			lines = append(lines, s[start:i])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		lines = append(lines, s[start:])
+	}
+	return lines
+}
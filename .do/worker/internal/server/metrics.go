@@ -0,0 +1,109 @@
+package server
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "do_worker_http_requests_total",
+		Help: "Total HTTP requests handled, labeled by route, method, and status.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "do_worker_http_request_duration_seconds",
+		Help: "HTTP request latency in seconds, labeled by route, method, and status.",
+	}, []string{"route", "method", "status"})
+
+	dbErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "do_worker_db_errors_total",
+		Help: "Total database errors returned to a handler, labeled by route.",
+	}, []string{"route"})
+
+	ftsQueryDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name: "do_worker_fts_query_duration_seconds",
+		Help: "Full-text search query latency in seconds, as seen by /api/search.",
+	})
+
+	activeSessions = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "do_worker_active_sessions",
+		Help: "Number of sessions currently open (created but not yet ended).",
+	})
+
+	observationsPerMinute = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "do_worker_observations_per_minute",
+		Help: "Observations created during the most recently completed one-minute window.",
+	})
+)
+
+// observationRate tallies observations created during the current window and
+// republishes observationsPerMinute whenever the window rolls over, so the
+// gauge reads correctly even without a Prometheus server computing rate().
+var observationRate = newWindowCounter(time.Minute, func(n int) {
+	observationsPerMinute.Set(float64(n))
+})
+
+// windowCounter counts events within a fixed window and reports the total to
+// publish once the window elapses, then resets for the next one.
+type windowCounter struct {
+	mu      sync.Mutex
+	count   int
+	publish func(int)
+}
+
+func newWindowCounter(window time.Duration, publish func(int)) *windowCounter {
+	wc := &windowCounter{publish: publish}
+	go func() {
+		ticker := time.NewTicker(window)
+		defer ticker.Stop()
+		for range ticker.C {
+			wc.mu.Lock()
+			n := wc.count
+			wc.count = 0
+			wc.mu.Unlock()
+			wc.publish(n)
+		}
+	}()
+	return wc
+}
+
+func (wc *windowCounter) incr() {
+	wc.mu.Lock()
+	wc.count++
+	wc.mu.Unlock()
+}
+
+// recordObservationCreated counts an observation toward the current
+// observations-per-minute window.
+func recordObservationCreated() {
+	observationRate.incr()
+}
+
+// recordDBError increments the database error counter for route.
+func recordDBError(route string) {
+	dbErrorsTotal.WithLabelValues(route).Inc()
+}
+
+// metricsMiddleware records request count and latency for every route,
+// labeled by route, method, and status.
+func metricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		httpRequestsTotal.WithLabelValues(route, c.Request.Method, status).Inc()
+		httpRequestDuration.WithLabelValues(route, c.Request.Method, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/do-focus/worker/internal/db"
+	"github.com/gin-gonic/gin"
+)
+
+// decodeCursorParam decodes the request's ?cursor= query param, returning
+// (nil, nil) when it's absent so handlers can treat that as "first page".
+func decodeCursorParam(c *gin.Context) (*db.Cursor, error) {
+	return db.DecodeCursor(c.Query("cursor"))
+}
+
+// writeNextLink sets a standard RFC 5988 Link: <url>; rel="next" header
+// pointing at the same request with ?cursor= replaced by nextCursor. It is
+// a no-op when nextCursor is empty (last page reached).
+func writeNextLink(c *gin.Context, nextCursor string) {
+	if nextCursor == "" {
+		return
+	}
+	q := c.Request.URL.Query()
+	q.Set("cursor", nextCursor)
+	c.Header("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, c.Request.URL.Path, q.Encode()))
+}
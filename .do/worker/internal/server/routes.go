@@ -3,14 +3,20 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	wctx "github.com/do-focus/worker/internal/context"
+	"github.com/do-focus/worker/internal/db"
+	"github.com/do-focus/worker/internal/summarizer"
 	"github.com/do-focus/worker/pkg/models"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // Version is set by main package
@@ -18,14 +24,26 @@ var Version = "dev"
 
 // setupRoutes configures all API routes.
 func (s *Server) setupRoutes() {
+	// Record request count/latency for every route before anything else runs.
+	s.router.Use(metricsMiddleware())
+
 	// Health check
 	s.router.GET("/health", s.handleHealth)
 
-	// API routes
+	// Prometheus metrics
+	s.router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// API routes. Every request must carry a valid bearer token; the
+	// middleware chain resolves it to a user/scopes, throttles per-user
+	// request rate, and stashes the effective user for handlers to read
+	// via c.MustGet("user").(string).
+	limiter := newRateLimiter()
 	api := s.router.Group("/api")
+	api.Use(s.authMiddleware(), rateLimitMiddleware(limiter), s.requestContextMiddleware())
 	{
 		// Context injection for SessionStart hook
 		api.GET("/context/inject", s.handleContextInject)
+		api.GET("/context/stream", s.handleContextStream)
 
 		// Session management
 		api.GET("/sessions", s.handleGetSessions)
@@ -37,6 +55,7 @@ func (s *Server) setupRoutes() {
 		api.GET("/observations", s.handleGetObservations)
 		api.GET("/observations/search", s.handleSearchObservations)
 		api.POST("/observations", s.handleCreateObservation)
+		api.POST("/observations/bulk", s.handleBulkCreateObservations)
 
 		// Summaries
 		api.GET("/summaries", s.handleGetSummaries)
@@ -54,12 +73,46 @@ func (s *Server) setupRoutes() {
 		api.GET("/plans", s.handleGetPlans)
 		api.POST("/plans", s.handleCreatePlan)
 
-		// Team context
-		api.GET("/team/context", s.handleTeamContext)
+		// Team context - reads across users, so it needs its own scope
+		// beyond the requester's own read/write grant.
+		api.GET("/team/context", requireScope("read:team"), s.handleTeamContext)
 
 		// Projects
 		api.GET("/projects", s.getProjects)
+
+		// Scopes
+		api.GET("/scopes", s.handleListScopes)
+		api.POST("/scopes", s.handleCreateScope)
+		api.POST("/scopes/:id/members", s.handleAddScopeMember)
+
+		api.GET("/tags", s.handleListTags)
+		api.PUT("/tags/:name", s.handleRenameTag)
+		api.GET("/observations/by-tags", s.handleGetObservationsByTags)
+
+		// Logs
+		api.GET("/logs", s.handleLogs)
+
+		// Dashboard stats
+		api.GET("/stats/daily", s.handleDailyActivity)
+		api.GET("/stats/observations/breakdown", s.handleObservationBreakdown)
+		api.GET("/stats/tags", s.handleTopTags)
+		api.GET("/stats/plans/completion", s.handlePlanCompletion)
+	}
+}
+
+// resolveScope derives the db.ScopeContext for a request from the
+// authenticated user (set by requestContextMiddleware) and the optional
+// "scope_id" query parameter, falling back to the per-user default scope
+// created by the scopes migration.
+func (s *Server) resolveScope(c *gin.Context) db.ScopeContext {
+	userName := c.MustGet("user").(string)
+
+	scopeID := c.Query("scope_id")
+	if scopeID == "" {
+		scopeID = "default-" + userName
 	}
+
+	return db.ScopeContext{ScopeID: scopeID, UserName: userName}
 }
 
 // handleHealth handles the health check endpoint.
@@ -90,16 +143,15 @@ func (s *Server) handleHealth(c *gin.Context) {
 // - level 1: minimal (session only)
 // - level 2: standard (session + observations) [default]
 // - level 3: full (session + observations + plan + team)
+//
+// Building and rendering is delegated to wctx.Builder, which always runs
+// its registered middlewares — including the default secret/PII Redactor —
+// against the assembled context before any renderer sees it, so redaction
+// isn't something a caller has to opt into.
 func (s *Server) handleContextInject(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	userName := c.Query("user")
-	if userName == "" {
-		userName = os.Getenv("DO_USER_NAME")
-	}
-	if userName == "" {
-		userName = "default"
-	}
+	scope := s.resolveScope(c)
 
 	// Parse level parameter (1-3, default 2)
 	levelStr := c.DefaultQuery("level", "2")
@@ -111,74 +163,76 @@ func (s *Server) handleContextInject(c *gin.Context) {
 		level = 3
 	}
 
-	// Get latest session (always included)
-	session, err := s.db.GetLatestSession(ctx, userName)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "database_error",
-			Message: err.Error(),
-		})
-		return
-	}
-
-	var observations []models.Observation
-	var plan *models.Plan
-	var teamContext []models.TeamContext
-
-	// Level 2+: Include observations
+	// Level 1 omits observations entirely; level 2+ fetches obs_limit
+	// (default/cap 20 at level 2, uncapped above that) via ObservationLimit.
+	obsLimit := -1
 	if level >= 2 {
 		limitStr := c.DefaultQuery("obs_limit", "20")
 		limit, _ := strconv.Atoi(limitStr)
 		if limit <= 0 {
 			limit = 20
 		}
-		// Adjust limit based on level
-		if level == 2 {
-			if limit > 20 {
-				limit = 20
-			}
+		if level == 2 && limit > 20 {
+			limit = 20
 		}
+		obsLimit = limit
+	}
 
-		observations, err = s.db.GetRecentObservations(ctx, userName, limit)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "database_error",
-				Message: err.Error(),
-			})
-			return
-		}
+	builder := wctx.NewBuilder(s.db).Use(wctx.NewRedactor().Middleware())
+	resp, err := builder.BuildContext(ctx, scope, wctx.BuildOptions{
+		ObservationLimit: obsLimit,
+		IncludePlan:      level >= 3,
+		IncludeTeam:      level >= 3,
+		IncludeSession:   true,
+		Format:           c.DefaultQuery("format", "markdown"),
+	})
+	if err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
 	}
 
-	// Level 3: Include plan and team context
-	if level >= 3 {
-		plan, err = s.db.GetActivePlan(ctx, userName)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "database_error",
-				Message: err.Error(),
-			})
-			return
-		}
+	c.JSON(http.StatusOK, resp)
+}
 
-		teamContext, err = s.db.GetTeamContext(ctx, userName)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   "database_error",
-				Message: err.Error(),
-			})
-			return
-		}
-	}
+// handleContextStream implements GET /api/context/stream: a long-lived
+// Server-Sent Events connection that pushes observation/session/plan/prompt
+// events as the mutating handlers Publish them, so IDE and hook
+// integrations can react live instead of polling /api/context/inject.
+func (s *Server) handleContextStream(c *gin.Context) {
+	scope := s.resolveScope(c)
+
+	events, cancel := s.hub.Subscribe(scope.UserName)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
 
-	// Build markdown response
-	markdown := buildContextMarkdown(session, observations, plan, teamContext)
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
 
-	c.JSON(http.StatusOK, models.ContextInjectResponse{
-		Session:      session,
-		Observations: observations,
-		ActivePlan:   plan,
-		TeamContext:  teamContext,
-		Markdown:     markdown,
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return false
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				return true
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+			return true
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
 	})
 }
 
@@ -193,8 +247,10 @@ func (s *Server) handleCreateSession(c *gin.Context) {
 		return
 	}
 
+	scope := s.resolveScope(c)
+
 	// Check if session already exists (idempotent)
-	existing, _ := s.db.GetSession(c.Request.Context(), req.ID)
+	existing, _ := s.db.GetSession(c.Request.Context(), scope, req.ID)
 	if existing != nil {
 		c.JSON(http.StatusOK, existing)
 		return
@@ -202,12 +258,14 @@ func (s *Server) handleCreateSession(c *gin.Context) {
 
 	session := &models.Session{
 		ID:        req.ID,
+		ScopeID:   scope.ScopeID,
 		UserName:  req.UserName,
 		ProjectID: req.ProjectID,
 		StartedAt: time.Now(),
 	}
 
-	if err := s.db.CreateSession(c.Request.Context(), session); err != nil {
+	if err := s.db.CreateSession(c.Request.Context(), scope, session); err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -215,12 +273,16 @@ func (s *Server) handleCreateSession(c *gin.Context) {
 		return
 	}
 
+	s.hub.Publish(scope.UserName, Event{Type: EventSessionStarted, ScopeID: scope.ScopeID, Data: session, CreatedAt: time.Now()})
+	activeSessions.Inc()
+
 	c.JSON(http.StatusCreated, session)
 }
 
 // handleEndSession handles session ending.
 func (s *Server) handleEndSession(c *gin.Context) {
 	id := c.Param("id")
+	scope := s.resolveScope(c)
 
 	var req models.EndSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -228,7 +290,8 @@ func (s *Server) handleEndSession(c *gin.Context) {
 		req = models.EndSessionRequest{}
 	}
 
-	if err := s.db.EndSession(c.Request.Context(), id, req.Summary); err != nil {
+	if err := s.db.EndSession(c.Request.Context(), scope, id, req.Summary); err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -236,10 +299,14 @@ func (s *Server) handleEndSession(c *gin.Context) {
 		return
 	}
 
+	s.hub.Publish(scope.UserName, Event{Type: EventSessionEnded, ScopeID: scope.ScopeID, Data: gin.H{"session_id": id}, CreatedAt: time.Now()})
+	activeSessions.Dec()
+
 	c.JSON(http.StatusOK, gin.H{"status": "ended"})
 }
 
-// handleGetSessions handles session list retrieval.
+// handleGetSessions handles session list retrieval, keyset-paginated via an
+// opaque ?cursor= (see db.Cursor) and ?limit=.
 func (s *Server) handleGetSessions(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "20")
 	limit, _ := strconv.Atoi(limitStr)
@@ -247,8 +314,15 @@ func (s *Server) handleGetSessions(c *gin.Context) {
 		limit = 20
 	}
 
-	sessions, err := s.db.GetRecentSessions(c.Request.Context(), limit)
+	cursor, err := decodeCursorParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid_cursor", Message: err.Error()})
+		return
+	}
+
+	sessions, err := s.db.ListSessionsAfter(c.Request.Context(), s.resolveScope(c), cursor, limit)
 	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -256,15 +330,25 @@ func (s *Server) handleGetSessions(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, sessions)
+	resp := models.SessionListResponse{Sessions: sessions, PrevCursor: c.Query("cursor")}
+	if len(sessions) == limit {
+		last := sessions[len(sessions)-1]
+		if next, err := db.EncodeCursor(db.Cursor{LastID: last.ID, LastCreatedAt: last.CreatedAt}); err == nil {
+			resp.NextCursor = next
+			writeNextLink(c, next)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
 // handleGetSession handles single session retrieval.
 func (s *Server) handleGetSession(c *gin.Context) {
 	id := c.Param("id")
 
-	session, err := s.db.GetSession(c.Request.Context(), id)
+	session, err := s.db.GetSession(c.Request.Context(), s.resolveScope(c), id)
 	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -283,15 +367,26 @@ func (s *Server) handleGetSession(c *gin.Context) {
 	c.JSON(http.StatusOK, session)
 }
 
-// handleGetObservations handles observation list retrieval.
+// handleGetObservations handles observation list retrieval, keyset-paginated
+// via an opaque ?cursor= (see db.Cursor) and ?limit=.
 func (s *Server) handleGetObservations(c *gin.Context) {
 	sessionID := c.Query("session_id")
 	obsType := c.Query("type")
 	limitStr := c.DefaultQuery("limit", "100")
 	limit, _ := strconv.Atoi(limitStr)
+	if limit <= 0 {
+		limit = 100
+	}
+
+	cursor, err := decodeCursorParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid_cursor", Message: err.Error()})
+		return
+	}
 
-	observations, err := s.db.GetObservationsFiltered(c.Request.Context(), sessionID, obsType, limit)
+	observations, err := s.db.ListObservationsAfter(c.Request.Context(), s.resolveScope(c), sessionID, obsType, cursor, limit)
 	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -299,7 +394,17 @@ func (s *Server) handleGetObservations(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, observations)
+	resp := models.ObservationListResponse{Observations: observations, PrevCursor: c.Query("cursor")}
+	if len(observations) == limit {
+		last := observations[len(observations)-1]
+		cur := db.Cursor{LastID: strconv.FormatInt(last.ID, 10), LastCreatedAt: last.CreatedAt}
+		if next, err := db.EncodeCursor(cur); err == nil {
+			resp.NextCursor = next
+			writeNextLink(c, next)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
 // handleSearchObservations handles observation search.
@@ -316,8 +421,11 @@ func (s *Server) handleSearchObservations(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "50")
 	limit, _ := strconv.Atoi(limitStr)
 
-	results, err := s.db.SearchObservations(c.Request.Context(), query, limit)
+	dbStart := time.Now()
+	results, err := s.db.SearchObservations(c.Request.Context(), s.resolveScope(c), query, limit)
+	dbElapsed := time.Since(dbStart)
 	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -325,18 +433,41 @@ func (s *Server) handleSearchObservations(c *gin.Context) {
 		return
 	}
 
+	if c.Query("stats") == "true" {
+		c.JSON(http.StatusOK, models.ObservationSearchResponse{
+			Results: results,
+			Stats: &models.SearchStats{
+				TotalRowsScanned: len(results),
+				DBTimeMs:         dbElapsed.Milliseconds(),
+				FTSTimeMs:        dbElapsed.Milliseconds(),
+			},
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, results)
 }
 
-// handleGetSummaries handles summary list retrieval.
+// handleGetSummaries handles summary list retrieval, keyset-paginated via an
+// opaque ?cursor= (see db.Cursor) and ?limit=.
 func (s *Server) handleGetSummaries(c *gin.Context) {
 	daysStr := c.DefaultQuery("days", "7")
 	days, _ := strconv.Atoi(daysStr)
 	limitStr := c.DefaultQuery("limit", "100")
 	limit, _ := strconv.Atoi(limitStr)
+	if limit <= 0 {
+		limit = 100
+	}
 
-	summaries, err := s.db.GetAllSummaries(c.Request.Context(), days, limit)
+	cursor, err := decodeCursorParam(c)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid_cursor", Message: err.Error()})
+		return
+	}
+
+	summaries, err := s.db.ListSummariesAfter(c.Request.Context(), s.resolveScope(c), days, cursor, limit)
+	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -344,17 +475,38 @@ func (s *Server) handleGetSummaries(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, summaries)
+	resp := models.SummaryListResponse{Summaries: summaries, PrevCursor: c.Query("cursor")}
+	if len(summaries) == limit {
+		last := summaries[len(summaries)-1]
+		cur := db.Cursor{LastID: strconv.FormatInt(last.ID, 10), LastCreatedAt: last.CreatedAt}
+		if next, err := db.EncodeCursor(cur); err == nil {
+			resp.NextCursor = next
+			writeNextLink(c, next)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
-// handleGetPlans handles plan list retrieval.
+// handleGetPlans handles plan list retrieval, keyset-paginated via an opaque
+// ?cursor= (see db.Cursor) and ?limit=.
 func (s *Server) handleGetPlans(c *gin.Context) {
 	sessionID := c.Query("session_id")
 	limitStr := c.DefaultQuery("limit", "50")
 	limit, _ := strconv.Atoi(limitStr)
+	if limit <= 0 {
+		limit = 50
+	}
+
+	cursor, err := decodeCursorParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid_cursor", Message: err.Error()})
+		return
+	}
 
-	plans, err := s.db.GetAllPlans(c.Request.Context(), sessionID, limit)
+	plans, err := s.db.ListPlansAfter(c.Request.Context(), s.resolveScope(c), sessionID, cursor, limit)
 	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -362,7 +514,17 @@ func (s *Server) handleGetPlans(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, plans)
+	resp := models.PlanListResponse{Plans: plans, PrevCursor: c.Query("cursor")}
+	if len(plans) == limit {
+		last := plans[len(plans)-1]
+		cur := db.Cursor{LastID: strconv.FormatInt(last.ID, 10), LastCreatedAt: last.CreatedAt}
+		if next, err := db.EncodeCursor(cur); err == nil {
+			resp.NextCursor = next
+			writeNextLink(c, next)
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
 // handleCreateObservation handles observation creation.
@@ -397,7 +559,9 @@ func (s *Server) handleCreateObservation(c *gin.Context) {
 		Tags:       tagsJSON,
 	}
 
-	if err := s.db.CreateObservation(c.Request.Context(), obs); err != nil {
+	scope := s.resolveScope(c)
+	if err := s.db.CreateObservation(c.Request.Context(), scope, obs); err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -405,9 +569,109 @@ func (s *Server) handleCreateObservation(c *gin.Context) {
 		return
 	}
 
+	s.hub.Publish(scope.UserName, Event{Type: EventObservationCreated, ScopeID: scope.ScopeID, Data: obs, CreatedAt: time.Now()})
+	recordObservationCreated()
+
 	c.JSON(http.StatusCreated, obs)
 }
 
+// bulkObservationBatchSize caps how many lines handleBulkCreateObservations
+// commits per transaction, bounding both lock hold time and how much of a
+// single-line error can roll back.
+const bulkObservationBatchSize = 500
+
+// bulkObservationResult is one line of handleBulkCreateObservations'
+// NDJSON response, reporting the outcome for the input line at Index.
+type bulkObservationResult struct {
+	Index int    `json:"index"`
+	ID    int64  `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// handleBulkCreateObservations handles bulk observation ingestion from a
+// newline-delimited JSON body (one CreateObservationRequest per line),
+// batching inserts into transactions of bulkObservationBatchSize rows via
+// db.CreateObservationsBatch. This lets hook scripts and log-import tools
+// replay a full session's observations in a handful of round-trips
+// instead of one request per observation. The response is itself NDJSON:
+// one {index, id, error?} per input line, streamed as each batch commits.
+func (s *Server) handleBulkCreateObservations(c *gin.Context) {
+	scope := s.resolveScope(c)
+	decoder := json.NewDecoder(c.Request.Body)
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(c.Writer)
+
+	var batch []*models.Observation
+	batchStart := 0
+	index := 0
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		err := s.db.CreateObservationsBatch(c.Request.Context(), scope, batch)
+		if err != nil {
+			recordDBError(c.FullPath())
+		}
+		for i, obs := range batch {
+			result := bulkObservationResult{Index: batchStart + i}
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.ID = obs.ID
+				s.hub.Publish(scope.UserName, Event{Type: EventObservationCreated, ScopeID: scope.ScopeID, Data: obs, CreatedAt: time.Now()})
+				recordObservationCreated()
+			}
+			encoder.Encode(result)
+		}
+		if f, ok := c.Writer.(http.Flusher); ok {
+			f.Flush()
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		var req models.CreateObservationRequest
+		if err := decoder.Decode(&req); err != nil {
+			if err == io.EOF {
+				break
+			}
+			flush()
+			encoder.Encode(bulkObservationResult{Index: index, Error: err.Error()})
+			return
+		}
+
+		if req.Importance <= 0 || req.Importance > 5 {
+			req.Importance = 3
+		}
+		var tagsJSON string
+		if len(req.Tags) > 0 {
+			tagsBytes, _ := json.Marshal(req.Tags)
+			tagsJSON = string(tagsBytes)
+		}
+
+		if len(batch) == 0 {
+			batchStart = index
+		}
+		batch = append(batch, &models.Observation{
+			SessionID:  req.SessionID,
+			AgentName:  req.AgentName,
+			Type:       req.Type,
+			Content:    req.Content,
+			Importance: req.Importance,
+			Tags:       tagsJSON,
+		})
+		index++
+
+		if len(batch) >= bulkObservationBatchSize {
+			flush()
+		}
+	}
+	flush()
+}
+
 // handleCreateSummary handles summary creation.
 func (s *Server) handleCreateSummary(c *gin.Context) {
 	var req models.CreateSummaryRequest
@@ -425,7 +689,8 @@ func (s *Server) handleCreateSummary(c *gin.Context) {
 		Content:   req.Content,
 	}
 
-	if err := s.db.CreateSummary(c.Request.Context(), summary); err != nil {
+	if err := s.db.CreateSummary(c.Request.Context(), s.resolveScope(c), summary); err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -455,7 +720,9 @@ func (s *Server) handleCreatePlan(c *gin.Context) {
 		FilePath:  req.FilePath,
 	}
 
-	if err := s.db.CreatePlan(c.Request.Context(), plan); err != nil {
+	scope := s.resolveScope(c)
+	if err := s.db.CreatePlan(c.Request.Context(), scope, plan); err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -463,18 +730,16 @@ func (s *Server) handleCreatePlan(c *gin.Context) {
 		return
 	}
 
+	s.hub.Publish(scope.UserName, Event{Type: EventPlanUpdated, ScopeID: scope.ScopeID, Data: plan, CreatedAt: time.Now()})
+
 	c.JSON(http.StatusCreated, plan)
 }
 
 // handleTeamContext handles team context retrieval.
 func (s *Server) handleTeamContext(c *gin.Context) {
-	userName := c.Query("exclude_user")
-	if userName == "" {
-		userName = os.Getenv("DO_USER_NAME")
-	}
-
-	contexts, err := s.db.GetTeamContext(c.Request.Context(), userName)
+	contexts, err := s.db.GetTeamContext(c.Request.Context(), s.resolveScope(c))
 	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -487,8 +752,9 @@ func (s *Server) handleTeamContext(c *gin.Context) {
 
 // getProjects handles project list retrieval.
 func (s *Server) getProjects(c *gin.Context) {
-	projects, err := s.db.GetProjects(c.Request.Context())
+	projects, err := s.db.GetProjects(c.Request.Context(), s.resolveScope(c))
 	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -499,9 +765,189 @@ func (s *Server) getProjects(c *gin.Context) {
 	c.JSON(http.StatusOK, projects)
 }
 
-// handleGenerateSummary generates a rule-based summary from session observations.
+// handleListScopes handles scope list retrieval for the requesting user.
+func (s *Server) handleListScopes(c *gin.Context) {
+	userName := c.MustGet("user").(string)
+
+	scopes, err := s.db.ListUserScopes(c.Request.Context(), userName)
+	if err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, scopes)
+}
+
+// handleCreateScope handles scope creation. The caller becomes the scope's
+// owner; ownership can't be assigned to anyone else via the request body,
+// since that would let any token holder create a scope "owned" by an
+// arbitrary username.
+func (s *Server) handleCreateScope(c *gin.Context) {
+	userName := c.MustGet("user").(string)
+
+	var req models.CreateScopeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	scope, err := s.db.CreateScope(c.Request.Context(), req.Name, userName)
+	if err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, scope)
+}
+
+// handleAddScopeMember handles adding or updating a scope member. The
+// caller must already be an owner or admin of the target scope: without
+// this check, and since resolveScope's default scope IDs are the
+// predictable "default-<username>", any authenticated token holder could
+// add themselves to an arbitrary user's scope and grant themselves full
+// access to it.
+func (s *Server) handleAddScopeMember(c *gin.Context) {
+	userName := c.MustGet("user").(string)
+	scopeID := c.Param("id")
+
+	callerRole, err := s.db.GetScopeMemberRole(c.Request.Context(), scopeID, userName)
+	if err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if callerRole != "owner" && callerRole != "admin" {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   "forbidden",
+			Message: "only a scope owner or admin can add members",
+		})
+		return
+	}
+
+	var req models.AddScopeMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.Role == "" {
+		req.Role = "member"
+	}
+
+	if err := s.db.AddScopeMember(c.Request.Context(), scopeID, req.UserName, req.Role); err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "added"})
+}
+
+// handleListTags handles tag autocomplete, optionally filtered by prefix.
+func (s *Server) handleListTags(c *gin.Context) {
+	prefix := c.Query("prefix")
+	limitStr := c.DefaultQuery("limit", "50")
+	limit, _ := strconv.Atoi(limitStr)
+
+	tags, err := s.db.ListTags(c.Request.Context(), s.resolveScope(c), prefix, limit)
+	if err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// handleRenameTag handles renaming a tag across every observation that
+// references it. The caller must be a member of a scope that actually uses
+// oldName — tags are a shared global table, so without that check any
+// token holder could rename a tag belonging to another tenant.
+func (s *Server) handleRenameTag(c *gin.Context) {
+	oldName := c.Param("name")
+
+	var req models.RenameTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	scope := s.resolveScope(c)
+	if err := s.db.RenameTag(c.Request.Context(), scope, oldName, req.NewName); err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "renamed"})
+}
+
+// handleGetObservationsByTags handles tag-based observation lookup. The
+// "tags" query param is comma-separated; "match_all" requires every tag to be
+// present rather than any.
+func (s *Server) handleGetObservationsByTags(c *gin.Context) {
+	tagsParam := c.Query("tags")
+	if tagsParam == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Query parameter 'tags' is required",
+		})
+		return
+	}
+	tags := strings.Split(tagsParam, ",")
+	matchAll := c.Query("match_all") == "true"
+	limitStr := c.DefaultQuery("limit", "50")
+	limit, _ := strconv.Atoi(limitStr)
+
+	observations, err := s.db.GetObservationsByTags(c.Request.Context(), s.resolveScope(c), tags, matchAll, limit)
+	if err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, observations)
+}
+
+// handleGenerateSummary generates a session summary. Mode "rule" (the
+// default) pattern-matches over observations; "llm" and "hybrid" hand off to
+// a summarizer.Summarizer, falling back to the rule-based summary on any
+// provider error so the endpoint never fails outright.
 func (s *Server) handleGenerateSummary(c *gin.Context) {
 	ctx := c.Request.Context()
+	scope := s.resolveScope(c)
 
 	var req models.GenerateSummaryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -513,8 +959,9 @@ func (s *Server) handleGenerateSummary(c *gin.Context) {
 	}
 
 	// 1. Verify session exists
-	session, err := s.db.GetSession(ctx, req.SessionID)
+	session, err := s.db.GetSession(ctx, scope, req.SessionID)
 	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -530,8 +977,9 @@ func (s *Server) handleGenerateSummary(c *gin.Context) {
 	}
 
 	// 2. Get observations for the session
-	observations, err := s.db.GetObservationsFiltered(ctx, req.SessionID, "", 100)
+	observations, err := s.db.GetObservationsFiltered(ctx, scope, req.SessionID, "", 100)
 	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -539,17 +987,49 @@ func (s *Server) handleGenerateSummary(c *gin.Context) {
 		return
 	}
 
-	// 3. Generate rule-based summary
+	// 3. Generate the summary per req.Mode (default: rule)
+	mode := req.Mode
+	if mode == "" {
+		mode = "rule"
+	}
+
 	summaryContent := generateRuleBasedSummary(observations, req.LastAssistantMessage)
+	provider := req.Provider
+	var usage summarizer.TokenUsage
+
+	if mode == "llm" || mode == "hybrid" {
+		prompt := "Write a Markdown session summary."
+		if mode == "hybrid" {
+			prompt = "Refine the following draft summary using the raw observations for more detail, keeping its structure:\n\n" + summaryContent
+		}
+
+		llmContent, llmUsage, err := summarizer.New(provider).Summarize(ctx, observations, req.LastAssistantMessage, prompt)
+		if err != nil {
+			// Provider unavailable: keep the rule-based summary rather than
+			// failing the request outright.
+			mode = "rule"
+		} else {
+			summaryContent = llmContent
+			usage = llmUsage
+		}
+	}
+	if provider == "" {
+		provider = os.Getenv("DO_LLM_PROVIDER")
+	}
 
 	// 4. Save summary to DB
 	summary := &models.Summary{
-		SessionID: req.SessionID,
-		Type:      "session",
-		Content:   summaryContent,
+		SessionID:        req.SessionID,
+		Type:             "session",
+		Content:          summaryContent,
+		Mode:             mode,
+		Provider:         provider,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
 	}
 
-	if err := s.db.CreateSummary(ctx, summary); err != nil {
+	if err := s.db.CreateSummary(ctx, scope, summary); err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -642,8 +1122,15 @@ func (s *Server) handleGetUserPrompts(c *gin.Context) {
 
 	sessionID := c.Query("session_id")
 
-	prompts, err := s.db.GetUserPrompts(ctx, sessionID, limit)
+	cursor, err := decodeCursorParam(c)
 	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid_cursor", Message: err.Error()})
+		return
+	}
+
+	prompts, err := s.db.ListUserPromptsAfter(ctx, sessionID, cursor, limit)
+	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -675,6 +1162,7 @@ func (s *Server) handleCreateUserPrompt(c *gin.Context) {
 	}
 
 	if err := s.db.CreateUserPrompt(ctx, prompt); err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -682,6 +1170,9 @@ func (s *Server) handleCreateUserPrompt(c *gin.Context) {
 		return
 	}
 
+	scope := s.resolveScope(c)
+	s.hub.Publish(scope.UserName, Event{Type: EventPromptCreated, ScopeID: scope.ScopeID, Data: prompt, CreatedAt: time.Now()})
+
 	c.JSON(http.StatusCreated, prompt)
 }
 
@@ -715,8 +1206,12 @@ func (s *Server) handleSearch(c *gin.Context) {
 		limit = 200
 	}
 
+	ftsStart := time.Now()
 	results, err := s.db.SearchFTS(ctx, query, types, limit)
+	ftsElapsed := time.Since(ftsStart)
+	ftsQueryDuration.Observe(ftsElapsed.Seconds())
 	if err != nil {
+		recordDBError(c.FullPath())
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "database_error",
 			Message: err.Error(),
@@ -724,68 +1219,121 @@ func (s *Server) handleSearch(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SearchResponse{
+	resp := models.SearchResponse{
 		Results: results,
 		Query:   query,
 		Total:   len(results),
-	})
+	}
+	if c.Query("stats") == "true" {
+		resp.Stats = &models.SearchStats{
+			TotalRowsScanned: len(results),
+			DBTimeMs:         ftsElapsed.Milliseconds(),
+			FTSTimeMs:        ftsElapsed.Milliseconds(),
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
 }
 
-// buildContextMarkdown builds a markdown representation of the context.
-func buildContextMarkdown(session *models.Session, observations []models.Observation, plan *models.Plan, team []models.TeamContext) string {
-	var md string
+// statsProvider returns the db.Adapter's optional StatsProvider capability,
+// writing a 501 response and false if the configured adapter doesn't back it.
+func (s *Server) statsProvider(c *gin.Context) (db.StatsProvider, bool) {
+	sp, ok := s.db.(db.StatsProvider)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, models.ErrorResponse{
+			Error:   "not_implemented",
+			Message: "stats are not supported by the configured database adapter",
+		})
+	}
+	return sp, ok
+}
+
+// handleDailyActivity handles daily session/observation activity stats.
+func (s *Server) handleDailyActivity(c *gin.Context) {
+	sp, ok := s.statsProvider(c)
+	if !ok {
+		return
+	}
 
-	md += "# Do Worker Context\n\n"
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
 
-	// Session info
-	if session != nil {
-		md += "## Last Session\n"
-		md += "- ID: " + session.ID + "\n"
-		md += "- Started: " + session.StartedAt.Format(time.RFC3339) + "\n"
-		if session.EndedAt != nil {
-			md += "- Ended: " + session.EndedAt.Format(time.RFC3339) + "\n"
-		}
-		if session.Summary != "" {
-			md += "- Summary: " + session.Summary + "\n"
-		}
-		md += "\n"
+	activity, err := sp.GetDailyActivity(c.Request.Context(), s.resolveScope(c), days)
+	if err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
 	}
 
-	// Active plan
-	if plan != nil {
-		md += "## Active Plan\n"
-		md += "**" + plan.Title + "**\n\n"
-		md += plan.Content + "\n\n"
+	c.JSON(http.StatusOK, activity)
+}
+
+// handleObservationBreakdown handles the per-type observation count stats.
+func (s *Server) handleObservationBreakdown(c *gin.Context) {
+	sp, ok := s.statsProvider(c)
+	if !ok {
+		return
 	}
 
-	// Recent observations
-	if len(observations) > 0 {
-		md += "## Recent Observations\n"
-		for _, obs := range observations {
-			importance := ""
-			if obs.Importance >= 4 {
-				importance = " [HIGH]"
-			}
-			md += "- [" + obs.Type + "]" + importance + " " + obs.Content
-			if obs.AgentName != "" {
-				md += " (by " + obs.AgentName + ")"
-			}
-			md += "\n"
-		}
-		md += "\n"
+	sessionID := c.Query("session_id")
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+
+	breakdown, err := sp.GetObservationTypeBreakdown(c.Request.Context(), s.resolveScope(c), sessionID, days)
+	if err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
 	}
 
-	// Team context
-	if len(team) > 0 {
-		md += "## Team Activity\n"
-		for _, t := range team {
-			md += "- **" + t.UserName + "**: " + t.Summary
-			if t.ActivePlan != "" {
-				md += " [Working on: " + t.ActivePlan + "]"
-			}
-			md += "\n"
-		}
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// handleTopTags handles the most-used-tags stats.
+func (s *Server) handleTopTags(c *gin.Context) {
+	sp, ok := s.statsProvider(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	tags, err := sp.GetTopTags(c.Request.Context(), s.resolveScope(c), limit)
+	if err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
 	}
 
-	return md
+	c.JSON(http.StatusOK, tags)
 }
+
+// handlePlanCompletion handles the plan draft/active/completed ratio stats.
+func (s *Server) handlePlanCompletion(c *gin.Context) {
+	sp, ok := s.statsProvider(c)
+	if !ok {
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+
+	rate, err := sp.GetPlanCompletionRate(c.Request.Context(), s.resolveScope(c), days)
+	if err != nil {
+		recordDBError(c.FullPath())
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "database_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, rate)
+}
+
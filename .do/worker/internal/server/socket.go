@@ -0,0 +1,41 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+)
+
+// ListenUnix binds a Unix domain socket at path for the worker to serve
+// HTTP over, instead of (or alongside) TCP. A stale socket left behind by an
+// unclean shutdown is removed first, mirroring how audience listeners
+// reclaim abandoned socket files. The socket is chmod'd to 0600 so only the
+// owning user can connect; callers should `defer os.Remove(path)` once done.
+func ListenUnix(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("socket: %s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("socket: failed to remove stale socket: %w", err)
+		}
+	}
+
+	addr, err := net.ResolveUnixAddr("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("socket: failed to resolve %s: %w", path, err)
+	}
+
+	ln, err := net.ListenUnix("unix", addr)
+	if err != nil {
+		return nil, fmt.Errorf("socket: failed to listen on %s: %w", path, err)
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		os.Remove(path)
+		return nil, fmt.Errorf("socket: failed to chmod %s: %w", path, err)
+	}
+
+	return ln, nil
+}
@@ -0,0 +1,93 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+type anthropicSummarizer struct {
+	apiKey  string
+	baseURL string
+	model   string
+}
+
+func newAnthropicSummarizer() *anthropicSummarizer {
+	baseURL := os.Getenv("DO_LLM_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com/v1"
+	}
+	model := os.Getenv("DO_LLM_MODEL")
+	if model == "" {
+		model = "claude-3-5-sonnet-latest"
+	}
+	return &anthropicSummarizer{
+		apiKey:  os.Getenv("ANTHROPIC_API_KEY"),
+		baseURL: baseURL,
+		model:   model,
+	}
+}
+
+func (a *anthropicSummarizer) Summarize(ctx context.Context, observations []models.Observation, lastMessage string, prompt string) (string, TokenUsage, error) {
+	if a.apiKey == "" {
+		return "", TokenUsage{}, fmt.Errorf("ANTHROPIC_API_KEY is not set")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model":      a.model,
+		"max_tokens": 1024,
+		"system":     systemPrompt,
+		"messages": []map[string]string{
+			{"role": "user", "content": buildUserMessage(observations, lastMessage, prompt)},
+		},
+	})
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/messages", bytes.NewReader(body))
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", a.apiKey)
+	httpReq.Header.Set("anthropic-version", "2023-06-01")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", TokenUsage{}, fmt.Errorf("anthropic: HTTP %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", TokenUsage{}, err
+	}
+	if len(out.Content) == 0 {
+		return "", TokenUsage{}, fmt.Errorf("anthropic: empty response")
+	}
+
+	return out.Content[0].Text, TokenUsage{
+		PromptTokens:     out.Usage.InputTokens,
+		CompletionTokens: out.Usage.OutputTokens,
+	}, nil
+}
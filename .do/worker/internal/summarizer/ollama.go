@@ -0,0 +1,75 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+type ollamaSummarizer struct {
+	baseURL string
+	model   string
+}
+
+func newOllamaSummarizer() *ollamaSummarizer {
+	baseURL := os.Getenv("DO_LLM_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	model := os.Getenv("DO_LLM_MODEL")
+	if model == "" {
+		model = "llama3"
+	}
+	return &ollamaSummarizer{baseURL: baseURL, model: model}
+}
+
+func (o *ollamaSummarizer) Summarize(ctx context.Context, observations []models.Observation, lastMessage string, prompt string) (string, TokenUsage, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model":  o.model,
+		"prompt": systemPrompt + "\n\n" + buildUserMessage(observations, lastMessage, prompt),
+		"stream": false,
+	})
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/api/generate", bytes.NewReader(body))
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", TokenUsage{}, fmt.Errorf("ollama: HTTP %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Response        string `json:"response"`
+		PromptEvalCount int    `json:"prompt_eval_count"`
+		EvalCount       int    `json:"eval_count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", TokenUsage{}, err
+	}
+	if out.Response == "" {
+		return "", TokenUsage{}, fmt.Errorf("ollama: empty response")
+	}
+
+	return out.Response, TokenUsage{
+		PromptTokens:     out.PromptEvalCount,
+		CompletionTokens: out.EvalCount,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package summarizer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+type openAISummarizer struct {
+	apiKey  string
+	baseURL string
+	model   string
+}
+
+func newOpenAISummarizer() *openAISummarizer {
+	baseURL := os.Getenv("DO_LLM_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	model := os.Getenv("DO_LLM_MODEL")
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	return &openAISummarizer{
+		apiKey:  os.Getenv("OPENAI_API_KEY"),
+		baseURL: baseURL,
+		model:   model,
+	}
+}
+
+func (o *openAISummarizer) Summarize(ctx context.Context, observations []models.Observation, lastMessage string, prompt string) (string, TokenUsage, error) {
+	if o.apiKey == "" {
+		return "", TokenUsage{}, fmt.Errorf("OPENAI_API_KEY is not set")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model": o.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": systemPrompt},
+			{"role": "user", "content": buildUserMessage(observations, lastMessage, prompt)},
+		},
+	})
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", TokenUsage{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", TokenUsage{}, fmt.Errorf("openai: HTTP %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", TokenUsage{}, err
+	}
+	if len(out.Choices) == 0 {
+		return "", TokenUsage{}, fmt.Errorf("openai: empty response")
+	}
+
+	return out.Choices[0].Message.Content, TokenUsage{
+		PromptTokens:     out.Usage.PromptTokens,
+		CompletionTokens: out.Usage.CompletionTokens,
+	}, nil
+}
@@ -0,0 +1,69 @@
+// Package summarizer turns a session's observations into a Markdown
+// summary using an LLM provider, as an alternative to the rule-based
+// pattern matching in internal/server.generateRuleBasedSummary.
+package summarizer
+
+import (
+	"context"
+	"os"
+
+	"github.com/do-focus/worker/pkg/models"
+)
+
+// TokenUsage records how many tokens a Summarize call consumed, persisted
+// on the resulting Summary row for cost tracking.
+type TokenUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Summarizer produces a Markdown session summary from raw observations, the
+// last assistant message, and a prompt describing what's wanted (a from-
+// scratch summary, or a rule-based skeleton to refine).
+type Summarizer interface {
+	Summarize(ctx context.Context, observations []models.Observation, lastMessage string, prompt string) (string, TokenUsage, error)
+}
+
+// New returns the Summarizer for provider ("openai", "anthropic", "ollama"),
+// each reading its endpoint/model/API key from the environment. provider
+// falls back to DO_LLM_PROVIDER, then "openai", when empty.
+func New(provider string) Summarizer {
+	if provider == "" {
+		provider = os.Getenv("DO_LLM_PROVIDER")
+	}
+	if provider == "" {
+		provider = "openai"
+	}
+
+	switch provider {
+	case "anthropic":
+		return newAnthropicSummarizer()
+	case "ollama":
+		return newOllamaSummarizer()
+	default:
+		return newOpenAISummarizer()
+	}
+}
+
+const systemPrompt = "You are an assistant that writes concise engineering session summaries in Markdown, using ## Completed / ## Decisions / ## Learnings sections."
+
+// renderObservations formats observations as a bullet list for inclusion in
+// an LLM prompt, in the order given, with type and content.
+func renderObservations(observations []models.Observation) string {
+	var out string
+	for _, obs := range observations {
+		out += "- [" + obs.Type + "] " + obs.Content + "\n"
+	}
+	return out
+}
+
+// buildUserMessage combines the caller's prompt/skeleton with the raw
+// observations and last assistant message so the model has everything it
+// needs in a single message.
+func buildUserMessage(observations []models.Observation, lastMessage string, prompt string) string {
+	msg := prompt + "\n\n## Raw observations\n" + renderObservations(observations)
+	if lastMessage != "" {
+		msg += "\n## Last assistant message\n" + lastMessage
+	}
+	return msg
+}
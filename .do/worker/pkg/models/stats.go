@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// DailyActivity is one day's bucket of a user's session and observation
+// activity, as returned by db.StatsProvider.GetDailyActivity.
+type DailyActivity struct {
+	Date             time.Time `json:"date"`
+	SessionCount     int       `json:"session_count"`
+	ObservationCount int       `json:"observation_count"`
+	AvgImportance    float64   `json:"avg_importance"`
+}
+
+// ObservationTypeCount is the number of observations recorded for a single
+// type (decision, pattern, learning, insight, ...).
+type ObservationTypeCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// TopTag is a tag and how often it appears across a user's observations.
+type TopTag struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// PlanCompletionRate summarizes plan status counts and the fraction
+// completed over the queried window.
+type PlanCompletionRate struct {
+	Draft      int     `json:"draft"`
+	Active     int     `json:"active"`
+	Completed  int     `json:"completed"`
+	Total      int     `json:"total"`
+	Completion float64 `json:"completion"`
+}
@@ -6,6 +6,7 @@ import "time"
 // Session represents a Claude session.
 type Session struct {
 	ID        string     `json:"id" db:"id"`
+	ScopeID   string     `json:"scope_id" db:"scope_id"`
 	UserName  string     `json:"user_name" db:"user_name"`
 	ProjectID string     `json:"project_id,omitempty" db:"project_id"`
 	StartedAt time.Time  `json:"started_at" db:"started_at"`
@@ -27,18 +28,92 @@ type Observation struct {
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 }
 
+// ObservationHit is an Observation returned from a full-text search, carrying
+// the combined relevance/recency/importance score it was ranked by.
+type ObservationHit struct {
+	Observation Observation `json:"observation"`
+	Score       float64     `json:"score"`
+}
+
+// SearchResponse is the result of a full-text search via GET /api/search.
+type SearchResponse struct {
+	Results []ObservationHit `json:"results"`
+	Query   string           `json:"query"`
+	Total   int              `json:"total"`
+	Stats   *SearchStats     `json:"stats,omitempty"`
+}
+
+// ObservationSearchResponse is the result of GET /api/observations/search
+// when the caller passes ?stats=true; without it the endpoint returns the
+// results array directly.
+type ObservationSearchResponse struct {
+	Results []Observation `json:"results"`
+	Stats   *SearchStats  `json:"stats"`
+}
+
+// SearchStats reports the cost of a search query, included on a search
+// response when the caller passes ?stats=true.
+type SearchStats struct {
+	TotalRowsScanned int   `json:"total_rows_scanned"`
+	DBTimeMs         int64 `json:"db_time_ms"`
+	FTSTimeMs        int64 `json:"fts_time_ms"`
+}
+
+// SessionListResponse is the cursor-paginated result of GET /api/sessions.
+// NextCursor is empty once the last page has been reached.
+type SessionListResponse struct {
+	Sessions   []Session `json:"sessions"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	PrevCursor string    `json:"prev_cursor,omitempty"`
+}
+
+// ObservationListResponse is the cursor-paginated result of GET
+// /api/observations.
+type ObservationListResponse struct {
+	Observations []Observation `json:"observations"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+	PrevCursor   string        `json:"prev_cursor,omitempty"`
+}
+
+// SummaryListResponse is the cursor-paginated result of GET /api/summaries.
+type SummaryListResponse struct {
+	Summaries  []Summary `json:"summaries"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	PrevCursor string    `json:"prev_cursor,omitempty"`
+}
+
+// PlanListResponse is the cursor-paginated result of GET /api/plans.
+type PlanListResponse struct {
+	Plans      []Plan `json:"plans"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// TagCount is a normalized tag and how many observations reference it, as
+// returned by db.Adapter.ListTags.
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
 // Summary represents a session or period summary.
 type Summary struct {
-	ID        int64     `json:"id" db:"id"`
-	SessionID string    `json:"session_id,omitempty" db:"session_id"`
-	Type      string    `json:"type" db:"type"` // session, daily, weekly
-	Content   string    `json:"content" db:"content"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID               int64     `json:"id" db:"id"`
+	ScopeID          string    `json:"scope_id" db:"scope_id"`
+	SessionID        string    `json:"session_id,omitempty" db:"session_id"`
+	Type             string    `json:"type" db:"type"` // session, daily, weekly
+	Content          string    `json:"content" db:"content"`
+	Mode             string    `json:"mode,omitempty" db:"mode"` // rule, llm, hybrid
+	Provider         string    `json:"provider,omitempty" db:"provider"`
+	PromptTokens     int       `json:"prompt_tokens,omitempty" db:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens,omitempty" db:"completion_tokens"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
 }
 
 // Plan represents a development plan.
 type Plan struct {
 	ID        int64     `json:"id" db:"id"`
+	ScopeID   string    `json:"scope_id" db:"scope_id"`
 	SessionID string    `json:"session_id,omitempty" db:"session_id"`
 	Title     string    `json:"title" db:"title"`
 	Content   string    `json:"content" db:"content"`
@@ -48,6 +123,23 @@ type Plan struct {
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Scope is a shared workspace (organization or team) that sessions, plans,
+// and summaries belong to. Membership is tracked in ScopeMember.
+type Scope struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	OwnerUser string    `json:"owner_user" db:"owner_user"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ScopeMember is a user's membership and role within a Scope.
+type ScopeMember struct {
+	ScopeID   string    `json:"scope_id" db:"scope_id"`
+	UserName  string    `json:"user_name" db:"user_name"`
+	Role      string    `json:"role" db:"role"` // owner, member
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
 // TeamContext represents context from team members.
 type TeamContext struct {
 	UserName     string    `json:"user_name" db:"user_name"`
@@ -66,11 +158,22 @@ type Project struct {
 
 // ContextInjectResponse is the response for context injection.
 type ContextInjectResponse struct {
-	Session      *Session       `json:"session,omitempty"`
-	Observations []Observation  `json:"observations,omitempty"`
-	ActivePlan   *Plan          `json:"active_plan,omitempty"`
-	TeamContext  []TeamContext  `json:"team_context,omitempty"`
-	Markdown     string         `json:"markdown"`
+	Session      *Session      `json:"session,omitempty"`
+	Observations []Observation `json:"observations,omitempty"`
+	ActivePlan   *Plan         `json:"active_plan,omitempty"`
+	TeamContext  []TeamContext `json:"team_context,omitempty"`
+	Markdown     string        `json:"markdown"`
+	// Format is the name of the renderer that produced Rendered (e.g.
+	// "markdown", "json", "yaml", "html", "text").
+	Format string `json:"format,omitempty"`
+	// Rendered holds the output of the selected renderer. For the default
+	// "markdown" format this is identical to Markdown.
+	Rendered []byte `json:"rendered,omitempty"`
+	// TerminalTail holds the last lines of the caller's terminal, captured
+	// via a TerminalSource when BuildOptions.IncludeTerminalCapture is set.
+	// Kept separate from Observations since it isn't DB-backed and has no
+	// Importance/Type classification of its own.
+	TerminalTail string `json:"terminal_tail,omitempty"`
 }
 
 // CreateSessionRequest is the request to create a new session.
@@ -102,6 +205,19 @@ type CreateSummaryRequest struct {
 	Content   string `json:"content" binding:"required"`
 }
 
+// GenerateSummaryRequest is the request to generate a session summary.
+// Mode selects how the summary is produced: "rule" (default, pattern
+// matching over observations), "llm" (provider writes the summary from
+// scratch), or "hybrid" (rule-based skeleton refined by the provider).
+// Provider is only consulted in "llm"/"hybrid" mode and defaults to
+// DO_LLM_PROVIDER.
+type GenerateSummaryRequest struct {
+	SessionID            string `json:"session_id" binding:"required"`
+	LastAssistantMessage string `json:"last_assistant_message,omitempty"`
+	Mode                 string `json:"mode,omitempty"`
+	Provider             string `json:"provider,omitempty"`
+}
+
 // CreatePlanRequest is the request to create a plan.
 type CreatePlanRequest struct {
 	SessionID string `json:"session_id,omitempty"`
@@ -110,6 +226,25 @@ type CreatePlanRequest struct {
 	FilePath  string `json:"file_path,omitempty"`
 }
 
+// CreateScopeRequest is the request to create a new scope. The owner is
+// always the authenticated caller (see handleCreateScope), not a client-
+// supplied field, so a token holder can't create a scope "owned" by
+// someone else.
+type CreateScopeRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// AddScopeMemberRequest is the request to add or update a scope member.
+type AddScopeMemberRequest struct {
+	UserName string `json:"user_name" binding:"required"`
+	Role     string `json:"role,omitempty"`
+}
+
+// RenameTagRequest is the request to rename a tag.
+type RenameTagRequest struct {
+	NewName string `json:"new_name" binding:"required"`
+}
+
 // HealthResponse is the health check response.
 type HealthResponse struct {
 	Status   string `json:"status"`
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/yejune/do-focus/internal/capture/ansi"
+	"github.com/yejune/do-focus/internal/capture/config"
 )
 
 // Terminal type detection
@@ -23,34 +28,19 @@ const (
 	Antigravity
 	Tmux
 	Screen
+	WezTerm
+	Kitty
+	Alacritty
+	WindowsTerminal
+	TTY
 )
 
+// detectTerminal returns the Type() of the first registry Capturer whose
+// Detect matches, or Unknown if none do.
 func detectTerminal() TerminalType {
-	// Check tmux first
-	if os.Getenv("TMUX") != "" {
-		return Tmux
-	}
-
-	// Check screen
-	if os.Getenv("STY") != "" {
-		return Screen
-	}
-
-	// Check TERM_PROGRAM env var
-	termProgram := os.Getenv("TERM_PROGRAM")
-	switch termProgram {
-	case "iTerm.app":
-		return ITerm2
-	case "Apple_Terminal":
-		return TerminalApp
-	case "vscode":
-		return VSCode
-	case "cursor":
-		return Cursor
-	case "antigravity":
-		return Antigravity
+	if c := detectCapturer(); c != nil {
+		return c.Type()
 	}
-
 	return Unknown
 }
 
@@ -102,6 +92,104 @@ func captureTmux(lines int) (string, error) {
 	return string(output), nil
 }
 
+// captureTmuxWithOptions runs tmux capture-pane against a specific
+// session/window/pane and scrollback range, used by capture profiles
+// instead of always capturing the current pane's entire history.
+func captureTmuxWithOptions(sel config.TmuxSelector, sb config.ScrollbackRange) (string, error) {
+	return captureTmuxTarget(tmuxTarget(sel), sb)
+}
+
+// captureTmuxTarget runs tmux capture-pane -t <target> directly, where
+// target is already a tmux pane-target string (session:window.pane); an
+// empty target captures the current pane. This is the shared primitive
+// behind captureTmuxWithOptions and --all-panes, which already has targets
+// in this form from `tmux list-panes`.
+func captureTmuxTarget(target string, sb config.ScrollbackRange) (string, error) {
+	start := sb.Start
+	if start == "" {
+		start = "-"
+	}
+	end := sb.End
+	if end == "" {
+		end = "-"
+	}
+
+	args := []string{"capture-pane", "-p", "-S", start, "-E", end}
+	if target != "" {
+		args = append(args, "-t", target)
+	}
+
+	cmd := exec.Command("tmux", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("tmux capture failed: %v", err)
+	}
+	return string(output), nil
+}
+
+// captureTmuxAllPanes captures every pane across every tmux session and
+// window without switching focus to any of them, concatenating each
+// pane's capture under a "=== target ===" header so the result can still
+// be grepped per pane.
+func captureTmuxAllPanes(sb config.ScrollbackRange) (string, error) {
+	out, err := exec.Command("tmux", "list-panes", "-a", "-F", "#{session_name}:#{window_index}.#{pane_index}").Output()
+	if err != nil {
+		return "", fmt.Errorf("tmux list-panes failed: %v", err)
+	}
+
+	targets := strings.Fields(strings.TrimSpace(string(out)))
+	var buf strings.Builder
+	for _, target := range targets {
+		content, err := captureTmuxTarget(target, sb)
+		if err != nil {
+			content = fmt.Sprintf("(capture failed: %v)\n", err)
+		}
+		fmt.Fprintf(&buf, "=== %s ===\n%s\n", target, content)
+	}
+	return buf.String(), nil
+}
+
+// captureTmuxPopup runs command inside a tmux popup (`display-popup -E`,
+// which closes the popup automatically once command exits) and captures
+// whatever it printed, mirroring fzf's --tmux popup pattern for commands
+// that are meant to be viewed interactively in their own pane rather than
+// piped.
+func captureTmuxPopup(command string) (string, error) {
+	tmpFile := filepath.Join(os.TempDir(), fmt.Sprintf("godo-capture-popup-%d.txt", os.Getpid()))
+	defer os.Remove(tmpFile)
+
+	shellCmd := fmt.Sprintf("(%s) >%s 2>&1", command, tmpFile)
+	cmd := exec.Command("tmux", "display-popup", "-E", shellCmd)
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tmux popup capture failed: %v", err)
+	}
+
+	data, err := os.ReadFile(tmpFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read popup output: %v", err)
+	}
+	return string(data), nil
+}
+
+// tmuxTarget builds a tmux -t target string (session:window.pane) from the
+// selector's non-empty fields, omitting any axis left blank.
+func tmuxTarget(sel config.TmuxSelector) string {
+	if sel.Session == "" && sel.Window == "" && sel.Pane == "" {
+		return ""
+	}
+	target := sel.Session
+	if sel.Window != "" {
+		target += ":" + sel.Window
+	}
+	if sel.Pane != "" {
+		if sel.Window == "" {
+			target += ":"
+		}
+		target += "." + sel.Pane
+	}
+	return target
+}
+
 func captureScreen(lines int) (string, error) {
 	// Capture entire scrollback buffer including history
 	tmpFile := "/tmp/screen-capture-" + strconv.Itoa(os.Getpid()) + ".txt"
@@ -133,9 +221,10 @@ func inTmuxSession() bool {
 		return true
 	}
 
-	// Fallback: check parent process for tmux
-	// This can be implemented by checking ps, but TMUX env var is more reliable
-	return false
+	// Fallback for IDE terminals and similar wrappers that don't always
+	// propagate TMUX to child processes: walk the parent-process chain
+	// looking for a tmux ancestor.
+	return processTreeHasAny("tmux")
 }
 
 type CaptureFallbackError struct {
@@ -146,23 +235,155 @@ func (e *CaptureFallbackError) Error() string {
 	return e.originalErr.Error()
 }
 
+// captureIDE captures an IDE-integrated terminal (VSCode, Cursor,
+// Antigravity). These terminals have no capture API of their own, so we
+// walk the process tree to find whichever multiplexer the IDE terminal
+// itself launched (tmux, or a wezterm/kitty instance attached via its mux
+// server) and delegate to that backend, rather than unconditionally
+// demanding a manual tmux session.
 func captureIDE(lines int) (string, error) {
-	// 1. Check if tmux is available
-	if !isTmuxAvailable() {
-		return "", &CaptureFallbackError{
-			originalErr: fmt.Errorf("IDE 터미널은 tmux 필요. 설치: brew install tmux"),
+	if inTmuxSession() {
+		if !isTmuxAvailable() {
+			return "", &CaptureFallbackError{
+				originalErr: fmt.Errorf("IDE 터미널은 tmux 필요. 설치: brew install tmux"),
+			}
+		}
+		return captureTmux(lines)
+	}
+	if processTreeHasAny("wezterm-mux-server", "wezterm") {
+		return captureWezTerm(lines)
+	}
+	if processTreeHasAny("kitty") {
+		return captureKitty(lines)
+	}
+
+	return "", &CaptureFallbackError{
+		originalErr: fmt.Errorf("IDE 터미널에서는 tmux 세션 필요"),
+	}
+}
+
+// targetToTerminalType maps a capture profile's Target string to the
+// TerminalType runCapture already knows how to handle, or runs the usual
+// auto-detection for "" / "auto".
+func targetToTerminalType(target string) (TerminalType, error) {
+	switch target {
+	case "", "auto":
+		return detectTerminal(), nil
+	case "iterm2":
+		return ITerm2, nil
+	case "terminal-app":
+		return TerminalApp, nil
+	case "tmux":
+		return Tmux, nil
+	case "screen":
+		return Screen, nil
+	case "wezterm":
+		return WezTerm, nil
+	case "kitty":
+		return Kitty, nil
+	case "alacritty":
+		return Alacritty, nil
+	case "windows-terminal":
+		return WindowsTerminal, nil
+	case "tty":
+		return TTY, nil
+	default:
+		return Unknown, fmt.Errorf("unknown capture target: %s", target)
+	}
+}
+
+// applyFilters post-processes a capture according to a profile's
+// FilterConfig: stripping ANSI escapes, dropping lines that don't match
+// Include or do match Exclude, and collapsing runs of blank lines.
+func applyFilters(content string, f config.FilterConfig) (string, error) {
+	lines := strings.Split(content, "\n")
+
+	if f.StripANSI {
+		for i, l := range lines {
+			lines[i] = ansi.Strip(l)
 		}
 	}
 
-	// 2. Check if currently in tmux session
-	if !inTmuxSession() {
-		return "", &CaptureFallbackError{
-			originalErr: fmt.Errorf("IDE 터미널에서는 tmux 세션 필요"),
+	var includeRe, excludeRe *regexp.Regexp
+	var err error
+	if f.Include != "" {
+		if includeRe, err = regexp.Compile(f.Include); err != nil {
+			return "", fmt.Errorf("invalid include filter: %w", err)
+		}
+	}
+	if f.Exclude != "" {
+		if excludeRe, err = regexp.Compile(f.Exclude); err != nil {
+			return "", fmt.Errorf("invalid exclude filter: %w", err)
+		}
+	}
+	if includeRe != nil || excludeRe != nil {
+		filtered := lines[:0]
+		for _, l := range lines {
+			if includeRe != nil && !includeRe.MatchString(l) {
+				continue
+			}
+			if excludeRe != nil && excludeRe.MatchString(l) {
+				continue
+			}
+			filtered = append(filtered, l)
 		}
+		lines = filtered
 	}
 
-	// 3. Capture from tmux session
-	return captureTmux(0)
+	if f.DedupeBlankLines {
+		deduped := lines[:0]
+		prevBlank := false
+		for _, l := range lines {
+			blank := strings.TrimSpace(l) == ""
+			if !(blank && prevBlank) {
+				deduped = append(deduped, l)
+			}
+			prevBlank = blank
+		}
+		lines = deduped
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// formatCapture renders content in the requested output format: "raw"
+// (the default, unmodified), "plain" (all SGR/CSI/OSC escapes stripped),
+// "html" (SGR colors/attributes rendered as <span style="..."> via the
+// ansi package), or "jsonl" (one {"ts":...,"text":...,"style":[...]}
+// object per line).
+func formatCapture(content, format string) (string, error) {
+	switch format {
+	case "", "raw":
+		return content, nil
+	case "plain":
+		return ansi.Strip(content), nil
+	case "html":
+		return ansi.HTML(content), nil
+	case "jsonl":
+		var sb strings.Builder
+		enc := json.NewEncoder(&sb)
+		for _, line := range ansi.JSONLines(content) {
+			if err := enc.Encode(line); err != nil {
+				return "", fmt.Errorf("encode jsonl line: %w", err)
+			}
+		}
+		return sb.String(), nil
+	default:
+		return "", fmt.Errorf("unknown capture format: %s", format)
+	}
+}
+
+// projectNameForCapture names the current project for a profile's
+// {project} destination placeholder: the git repo's directory name, or
+// the current working directory's if this isn't a git checkout.
+func projectNameForCapture() string {
+	if out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		return filepath.Base(strings.TrimSpace(string(out)))
+	}
+	if wd, err := os.Getwd(); err == nil {
+		return filepath.Base(wd)
+	}
+	return "unknown"
 }
 
 func limitLines(content string, lines int) string {
@@ -248,7 +469,12 @@ func copySessionLogFallback(outputPath string) error {
 func runCapture() {
 	// Parse flags
 	var outputPath string
+	var profileName string
 	var lines int = 0 // 0 = capture entire scrollback buffer
+	var tmuxPane, tmuxWindow, tmuxSession string
+	var allPanes bool
+	var popupCmd string
+	var formatFlag string
 
 	args := os.Args[2:]
 	for i := 0; i < len(args); i++ {
@@ -261,6 +487,14 @@ func runCapture() {
 				fmt.Println("Error: --output requires a path")
 				os.Exit(1)
 			}
+		case "--profile", "-p":
+			if i+1 < len(args) {
+				profileName = args[i+1]
+				i++
+			} else {
+				fmt.Println("Error: --profile requires a name")
+				os.Exit(1)
+			}
 		case "--lines", "-n":
 			// Note: --lines is ignored; always captures entire scrollback
 			if i+1 < len(args) {
@@ -269,6 +503,48 @@ func runCapture() {
 				fmt.Println("Error: --lines requires a number")
 				os.Exit(1)
 			}
+		case "--pane":
+			if i+1 < len(args) {
+				tmuxPane = args[i+1]
+				i++
+			} else {
+				fmt.Println("Error: --pane requires a pane index")
+				os.Exit(1)
+			}
+		case "--window":
+			if i+1 < len(args) {
+				tmuxWindow = args[i+1]
+				i++
+			} else {
+				fmt.Println("Error: --window requires a window index")
+				os.Exit(1)
+			}
+		case "--session":
+			if i+1 < len(args) {
+				tmuxSession = args[i+1]
+				i++
+			} else {
+				fmt.Println("Error: --session requires a session name")
+				os.Exit(1)
+			}
+		case "--all-panes":
+			allPanes = true
+		case "--format":
+			if i+1 < len(args) {
+				formatFlag = args[i+1]
+				i++
+			} else {
+				fmt.Println("Error: --format requires a value")
+				os.Exit(1)
+			}
+		case "--popup":
+			if i+1 < len(args) {
+				popupCmd = args[i+1]
+				i++
+			} else {
+				fmt.Println("Error: --popup requires a command")
+				os.Exit(1)
+			}
 		default:
 			fmt.Printf("Error: unknown flag: %s\n", args[i])
 			printCaptureUsage()
@@ -276,46 +552,132 @@ func runCapture() {
 		}
 	}
 
+	var profile *config.Profile
+	if profileName != "" {
+		cfgPath, err := config.DefaultPath()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		cfg, err := config.Load(cfgPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		p, err := cfg.Profile(profileName)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		profile = p
+	}
+
+	if outputPath == "" && profile != nil {
+		outputPath = profile.RenderDestination(map[string]string{"project": projectNameForCapture()})
+	}
 	if outputPath == "" {
 		fmt.Println("Error: --output is required")
 		printCaptureUsage()
 		os.Exit(1)
 	}
 
-	// Detect terminal type
-	termType := detectTerminal()
-	var content string
-	var err error
+	if formatFlag == "" && profile != nil {
+		formatFlag = profile.Format
+	}
 
-	// Capture based on terminal type
-	switch termType {
-	case ITerm2:
-		fmt.Println("Detected: iTerm2")
-		content, err = captureITerm2(lines)
-	case TerminalApp:
-		fmt.Println("Detected: Terminal.app")
-		content, err = captureTerminalApp(lines)
-	case Tmux:
-		fmt.Println("Detected: tmux")
-		content, err = captureTmux(lines)
-	case Screen:
-		fmt.Println("Detected: screen")
-		content, err = captureScreen(lines)
-	case VSCode:
-		fmt.Println("Detected: IDE Terminal (VSCode)")
-		content, err = captureIDE(lines)
-	case Cursor:
-		fmt.Println("Detected: IDE Terminal (Cursor)")
-		content, err = captureIDE(lines)
-	case Antigravity:
-		fmt.Println("Detected: IDE Terminal (Antigravity)")
-		content, err = captureIDE(lines)
-	default:
+	// --popup, --all-panes, and --pane/--window/--session explicitly ask
+	// for a specific tmux target rather than the usual auto-detected
+	// current-terminal capture, so they're handled before (and instead of)
+	// the Capturer dispatch below.
+	if popupCmd != "" {
+		content, err := captureTmuxPopup(popupCmd)
+		if err == nil {
+			content, err = formatCapture(content, formatFlag)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := writeCaptureOutput(outputPath, content); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if allPanes {
+		content, err := captureTmuxAllPanes(config.ScrollbackRange{})
+		if err == nil {
+			content, err = formatCapture(content, formatFlag)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := writeCaptureOutput(outputPath, content); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if tmuxPane != "" || tmuxWindow != "" || tmuxSession != "" {
+		sel := config.TmuxSelector{Session: tmuxSession, Window: tmuxWindow, Pane: tmuxPane}
+		content, err := captureTmuxWithOptions(sel, config.ScrollbackRange{})
+		if err == nil {
+			content, err = formatCapture(content, formatFlag)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := writeCaptureOutput(outputPath, content); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Pick a Capturer: the profile's forced Target if one was given,
+	// otherwise whichever registry entry auto-detects the current terminal.
+	var capturer Capturer
+	if profile != nil {
+		tt, err := targetToTerminalType(profile.Target)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		capturer = capturerForType(tt)
+		if capturer == nil {
+			fmt.Printf("Error: capture target %q is not supported\n", profile.Target)
+			os.Exit(1)
+		}
+	} else {
+		capturer = detectCapturer()
+	}
+
+	if capturer == nil {
 		fmt.Println("Error: Unable to detect terminal type")
-		fmt.Println("Supported: iTerm2, Terminal.app, tmux, screen, VSCode, Cursor, Antigravity")
+		fmt.Println("Supported: iTerm2, Terminal.app, tmux, screen, WezTerm, Kitty, Alacritty, Windows Terminal, VSCode, Cursor, Antigravity")
 		fmt.Println("Current TERM_PROGRAM:", os.Getenv("TERM_PROGRAM"))
 		os.Exit(1)
 	}
+	fmt.Println("Detected:", capturer.Name())
+
+	termType := capturer.Type()
+	var content string
+	var err error
+
+	if termType == Tmux && profile != nil {
+		content, err = captureTmuxWithOptions(profile.Tmux, profile.Scrollback)
+	} else {
+		var rc io.ReadCloser
+		rc, err = capturer.Capture(CaptureOptions{Lines: lines})
+		if err == nil {
+			defer rc.Close()
+			var data []byte
+			data, err = io.ReadAll(rc)
+			content = string(data)
+		}
+	}
 
 	if err != nil {
 		// Check if this is a fallback-eligible error
@@ -359,36 +721,95 @@ func runCapture() {
 		os.Exit(1)
 	}
 
-	// Write entire buffer to file
-	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
-		fmt.Printf("Error: failed to write file: %v\n", err)
+	if profile != nil {
+		filtered, err := applyFilters(content, profile.Filters)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		content = filtered
+	}
+
+	formatted, err := formatCapture(content, formatFlag)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
+	content = formatted
+
+	if err := writeCaptureOutput(outputPath, content); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// writeCaptureOutput writes content to outputPath, creating its parent
+// directory if needed, and prints the same completion summary every
+// capture path (normal, --popup, --all-panes, --pane/--window/--session)
+// reports on success.
+func writeCaptureOutput(outputPath, content string) error {
+	if dir := filepath.Dir(outputPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %v", err)
+		}
+	}
+	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
 
 	lineCount := len(strings.Split(strings.TrimSpace(content), "\n"))
 	fmt.Printf("✓ 터미널 캡처 완료: %s (%d줄)\n", outputPath, lineCount)
+	return nil
 }
 
 func printCaptureUsage() {
 	fmt.Println(`Usage: godo capture --output <file> [--lines <number>]
+       godo capture --profile <name>
 
 Options:
-  --output, -o <file>    Output file path (required)
+  --output, -o <file>    Output file path (required unless --profile sets one)
   --lines, -n <number>   Number of lines to capture (default: 500)
+  --profile, -p <name>   Use a named profile from ~/.do/capture.yml instead
+                         of ad-hoc flags (target terminal, tmux selector,
+                         scrollback range, filters, destination template)
+  --session <name>       Capture a specific tmux session instead of the
+                         current one (implies tmux; combine with --window/--pane)
+  --window <index>       Capture a specific tmux window instead of the current one
+  --pane <index>         Capture a specific tmux pane instead of the current one
+  --all-panes            Capture every pane in every tmux session/window,
+                         concatenated with a header per pane
+  --popup <command>      Run command in a dismissable tmux popup and capture
+                         its output (mirrors fzf's --tmux popup pattern)
+  --format <format>      Output encoding: raw (default), plain (ANSI stripped),
+                         html (SGR colors as <span style>), or jsonl
+                         (one {"ts":...,"text":...,"style":[...]} per line)
 
 Examples:
   godo capture --output terminal.txt
   godo capture --output debug.txt --lines 1000
   godo capture -o output.txt -n 200
+  godo capture --profile debug
+  godo capture --output side-pane.txt --session work --window 2 --pane 1
+  godo capture --output all-panes.txt --all-panes
+  godo capture --output status.txt --popup "htop -n 1"
+  godo capture --output terminal.html --format html
 
 Supported terminals:
   - iTerm2
   - Terminal.app (macOS)
   - tmux
   - screen
+  - WezTerm
+  - Kitty (requires allow_remote_control in kitty.conf)
+  - Alacritty (requires tmux)
+  - Windows Terminal
   - VSCode (requires tmux)
   - Cursor (requires tmux)
   - Antigravity (requires tmux)
+  - tty (last-resort fallback: opens /dev/tty directly and reports size/
+    cursor position via query sequences when no richer backend detects;
+    fixes stdout-redirected and empty-TERM_PROGRAM/SSH cases that otherwise
+    fall through to Unknown)
 
 Note: IDE terminals (VSCode, Cursor, Antigravity) require tmux to be installed and running.`)
 }
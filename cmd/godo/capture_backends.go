@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/yejune/do-focus/internal/capture/tty"
+)
+
+// Capturer captures one terminal's visible/scrollback buffer. runCapture
+// dispatches to the first Capturer in captureRegistry whose Detect matches,
+// instead of hand-rolling a terminal-by-terminal switch.
+type Capturer interface {
+	// Type identifies which TerminalType this backend implements, for
+	// display and for the IDE-terminal fallback-on-error logic.
+	Type() TerminalType
+	// Name is the human-readable label printed as "Detected: <name>".
+	Name() string
+	// Detect reports whether this backend's terminal is the active one.
+	Detect() bool
+	// Capture returns the captured buffer as a stream the caller must
+	// Close.
+	Capture(opts CaptureOptions) (io.ReadCloser, error)
+}
+
+// CaptureOptions controls how much of the buffer a Capturer returns.
+type CaptureOptions struct {
+	// Lines is passed to the backend's capture function. Only the Windows
+	// Terminal backend trims its output to the last Lines lines (when
+	// Lines > 0); every other backend returns everything available
+	// whatever its value.
+	Lines int
+}
+
+// captureRegistry lists every known Capturer backend in detection-priority
+// order: environment markers that are unambiguous (tmux, screen, WezTerm,
+// Kitty, Windows Terminal) before the TERM_PROGRAM-based ones that other
+// multiplexers sometimes also set, Alacritty next since it has no native
+// detection signal beyond TERM_PROGRAM either, and ttyCapturer last as the
+// true last resort: it detects whenever /dev/tty is openable at all, so it
+// must never shadow a more specific backend.
+func captureRegistry() []Capturer {
+	return []Capturer{
+		tmuxCapturer{},
+		screenCapturer{},
+		weztermCapturer{},
+		kittyCapturer{},
+		windowsTerminalCapturer{},
+		itermCapturer{},
+		terminalAppCapturer{},
+		ideCapturer{name: "VSCode", termType: VSCode, termProgram: "vscode"},
+		ideCapturer{name: "Cursor", termType: Cursor, termProgram: "cursor"},
+		ideCapturer{name: "Antigravity", termType: Antigravity, termProgram: "antigravity"},
+		alacrittyCapturer{},
+		ttyCapturer{},
+	}
+}
+
+// detectCapturer returns the first registry entry whose Detect matches, or
+// nil if none do.
+func detectCapturer() Capturer {
+	for _, c := range captureRegistry() {
+		if c.Detect() {
+			return c
+		}
+	}
+	return nil
+}
+
+// capturerForType returns the registry entry backing tt, used when a
+// capture profile forces a specific Target rather than relying on
+// auto-detection.
+func capturerForType(tt TerminalType) Capturer {
+	for _, c := range captureRegistry() {
+		if c.Type() == tt {
+			return c
+		}
+	}
+	return nil
+}
+
+// stringCapture adapts one of the existing captureXxx(lines int) (string,
+// error) functions to the Capturer.Capture signature.
+func stringCapture(content string, err error) (io.ReadCloser, error) {
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+type tmuxCapturer struct{}
+
+func (tmuxCapturer) Type() TerminalType { return Tmux }
+func (tmuxCapturer) Name() string       { return "tmux" }
+func (tmuxCapturer) Detect() bool       { return os.Getenv("TMUX") != "" }
+func (tmuxCapturer) Capture(opts CaptureOptions) (io.ReadCloser, error) {
+	return stringCapture(captureTmux(opts.Lines))
+}
+
+type screenCapturer struct{}
+
+func (screenCapturer) Type() TerminalType { return Screen }
+func (screenCapturer) Name() string       { return "screen" }
+func (screenCapturer) Detect() bool       { return os.Getenv("STY") != "" }
+func (screenCapturer) Capture(opts CaptureOptions) (io.ReadCloser, error) {
+	return stringCapture(captureScreen(opts.Lines))
+}
+
+type itermCapturer struct{}
+
+func (itermCapturer) Type() TerminalType { return ITerm2 }
+func (itermCapturer) Name() string       { return "iTerm2" }
+func (itermCapturer) Detect() bool       { return os.Getenv("TERM_PROGRAM") == "iTerm.app" }
+func (itermCapturer) Capture(opts CaptureOptions) (io.ReadCloser, error) {
+	return stringCapture(captureITerm2(opts.Lines))
+}
+
+type terminalAppCapturer struct{}
+
+func (terminalAppCapturer) Type() TerminalType { return TerminalApp }
+func (terminalAppCapturer) Name() string       { return "Terminal.app" }
+func (terminalAppCapturer) Detect() bool       { return os.Getenv("TERM_PROGRAM") == "Apple_Terminal" }
+func (terminalAppCapturer) Capture(opts CaptureOptions) (io.ReadCloser, error) {
+	return stringCapture(captureTerminalApp(opts.Lines))
+}
+
+// ideCapturer handles the IDE-integrated terminals (VSCode, Cursor,
+// Antigravity), which all capture the same way, through captureIDE: via a
+// tmux session running inside them, or failing that a wezterm or kitty
+// ancestor process. The three only differ in TERM_PROGRAM and label.
+type ideCapturer struct {
+	name        string
+	termType    TerminalType
+	termProgram string
+}
+
+func (c ideCapturer) Type() TerminalType { return c.termType }
+func (c ideCapturer) Name() string       { return "IDE Terminal (" + c.name + ")" }
+func (c ideCapturer) Detect() bool       { return os.Getenv("TERM_PROGRAM") == c.termProgram }
+func (c ideCapturer) Capture(opts CaptureOptions) (io.ReadCloser, error) {
+	return stringCapture(captureIDE(opts.Lines))
+}
+
+type weztermCapturer struct{}
+
+func (weztermCapturer) Type() TerminalType { return WezTerm }
+func (weztermCapturer) Name() string       { return "WezTerm" }
+func (weztermCapturer) Detect() bool       { return os.Getenv("TERM_PROGRAM") == "WezTerm" }
+func (weztermCapturer) Capture(opts CaptureOptions) (io.ReadCloser, error) {
+	return stringCapture(captureWezTerm(opts.Lines))
+}
+
+// captureWezTerm shells out to `wezterm cli get-text`, which prints the
+// pane's visible contents plus scrollback. If WEZTERM_PANE is set (it is,
+// inside any wezterm-spawned shell), it targets that pane explicitly so the
+// result is correct even if the caller isn't in the foreground pane.
+func captureWezTerm(lines int) (string, error) {
+	args := []string{"cli", "get-text"}
+	if pane := os.Getenv("WEZTERM_PANE"); pane != "" {
+		args = append(args, "--pane-id", pane)
+	}
+
+	cmd := exec.Command("wezterm", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("wezterm capture failed: %v", err)
+	}
+	return string(output), nil
+}
+
+type kittyCapturer struct{}
+
+func (kittyCapturer) Type() TerminalType { return Kitty }
+func (kittyCapturer) Name() string       { return "Kitty" }
+func (kittyCapturer) Detect() bool {
+	return os.Getenv("TERM") == "xterm-kitty" || os.Getenv("KITTY_WINDOW_ID") != ""
+}
+func (kittyCapturer) Capture(opts CaptureOptions) (io.ReadCloser, error) {
+	return stringCapture(captureKitty(opts.Lines))
+}
+
+// captureKitty shells out to `kitty @ get-text`, which requires the
+// kitty.conf setting `allow_remote_control yes` (or a `listen_on` socket) to
+// be enabled; without it kitty rejects the remote-control request and this
+// returns that error as-is so the user knows what to enable.
+func captureKitty(lines int) (string, error) {
+	cmd := exec.Command("kitty", "@", "get-text", "--match", "state:active")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("kitty capture failed (requires allow_remote_control in kitty.conf): %v", err)
+	}
+	return string(output), nil
+}
+
+type alacrittyCapturer struct{}
+
+func (alacrittyCapturer) Type() TerminalType { return Alacritty }
+func (alacrittyCapturer) Name() string       { return "Alacritty" }
+func (alacrittyCapturer) Detect() bool {
+	return os.Getenv("TERM_PROGRAM") == "alacritty" || os.Getenv("ALACRITTY_SOCKET") != ""
+}
+func (alacrittyCapturer) Capture(opts CaptureOptions) (io.ReadCloser, error) {
+	return stringCapture(captureAlacritty(opts.Lines))
+}
+
+// captureAlacritty has no native scrollback query to fall back on: unlike
+// wezterm/kitty, Alacritty's IPC socket (ALACRITTY_SOCKET) only carries
+// config-reload and window-spawn messages, not buffer contents. The only
+// supported path is running inside tmux and deferring to captureTmux.
+func captureAlacritty(lines int) (string, error) {
+	if !isTmuxAvailable() || !inTmuxSession() {
+		return "", fmt.Errorf("Alacritty capture requires running inside tmux (no native scrollback API)")
+	}
+	return captureTmux(lines)
+}
+
+type windowsTerminalCapturer struct{}
+
+func (windowsTerminalCapturer) Type() TerminalType { return WindowsTerminal }
+func (windowsTerminalCapturer) Name() string       { return "Windows Terminal" }
+func (windowsTerminalCapturer) Detect() bool       { return os.Getenv("WT_SESSION") != "" }
+func (windowsTerminalCapturer) Capture(opts CaptureOptions) (io.ReadCloser, error) {
+	return stringCapture(captureWindowsTerminal(opts.Lines))
+}
+
+// ttyCapturer is the last-resort backend: it opens /dev/tty directly
+// (rather than relying on stdio, which may be redirected) and reports what
+// query sequences can honestly tell us (size, cursor position, capability),
+// not actual buffer content — see internal/capture/tty's package doc for
+// why no query sequence can return that. Detect succeeds whenever /dev/tty
+// is openable at all, so this must stay last in captureRegistry.
+type ttyCapturer struct{}
+
+func (ttyCapturer) Type() TerminalType { return TTY }
+func (ttyCapturer) Name() string       { return "tty (query fallback)" }
+func (ttyCapturer) Detect() bool {
+	f, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+func (ttyCapturer) Capture(opts CaptureOptions) (io.ReadCloser, error) {
+	t, err := tty.Open()
+	if err != nil {
+		return nil, fmt.Errorf("tty capture failed: %w", err)
+	}
+	defer t.Close()
+	return stringCapture(t.TakeSnapshot().Report(), nil)
+}
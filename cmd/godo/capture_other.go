@@ -0,0 +1,11 @@
+//go:build !windows
+
+package main
+
+import "fmt"
+
+// captureWindowsTerminal is only implemented on Windows, where it reads the
+// console screen buffer directly; Windows Terminal itself only runs there.
+func captureWindowsTerminal(lines int) (string, error) {
+	return "", fmt.Errorf("Windows Terminal capture is only available when running on Windows")
+}
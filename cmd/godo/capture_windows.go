@@ -0,0 +1,93 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                        = syscall.NewLazyDLL("kernel32.dll")
+	procGetStdHandle                = kernel32.NewProc("GetStdHandle")
+	procGetConsoleScreenBufferInfo  = kernel32.NewProc("GetConsoleScreenBufferInfo")
+	procReadConsoleOutputCharacterW = kernel32.NewProc("ReadConsoleOutputCharacterW")
+)
+
+const stdOutputHandle = ^uintptr(11) + 1 // STD_OUTPUT_HANDLE = -11
+
+type coord struct {
+	X, Y int16
+}
+
+type smallRect struct {
+	Left, Top, Right, Bottom int16
+}
+
+type consoleScreenBufferInfo struct {
+	Size              coord
+	CursorPosition    coord
+	Attributes        uint16
+	Window            smallRect
+	MaximumWindowSize coord
+}
+
+// captureWindowsTerminal reads the console screen buffer directly via the
+// Win32 console API (GetConsoleScreenBufferInfo + ReadConsoleOutputCharacterW)
+// rather than shelling out, since Windows Terminal itself exposes no CLI
+// equivalent to `tmux capture-pane`. This only sees the buffer's current
+// size (including scrollback that's still within the buffer's row count),
+// not an unbounded history the way tmux does.
+func captureWindowsTerminal(lines int) (string, error) {
+	h, _, err := procGetStdHandle.Call(stdOutputHandle)
+	if h == 0 || h == ^uintptr(0) {
+		return "", fmt.Errorf("windows terminal capture failed: GetStdHandle: %v", err)
+	}
+
+	var info consoleScreenBufferInfo
+	r, _, err := procGetConsoleScreenBufferInfo.Call(h, uintptr(unsafe.Pointer(&info)))
+	if r == 0 {
+		return "", fmt.Errorf("windows terminal capture failed: GetConsoleScreenBufferInfo: %v", err)
+	}
+
+	width := int(info.Size.X)
+	height := int(info.Size.Y)
+	if width <= 0 || height <= 0 {
+		return "", fmt.Errorf("windows terminal capture failed: empty console buffer")
+	}
+
+	var rowsBuf strings.Builder
+	row := make([]uint16, width)
+	for y := 0; y < height; y++ {
+		var read uint32
+		origin := coord{X: 0, Y: int16(y)}
+		r, _, err := procReadConsoleOutputCharacterW.Call(
+			h,
+			uintptr(unsafe.Pointer(&row[0])),
+			uintptr(width),
+			uintptr(*(*uint32)(unsafe.Pointer(&origin))),
+			uintptr(unsafe.Pointer(&read)),
+		)
+		if r == 0 {
+			return "", fmt.Errorf("windows terminal capture failed: ReadConsoleOutputCharacterW: %v", err)
+		}
+		rowsBuf.WriteString(strings.TrimRight(string(utf16ToString(row[:read])), " "))
+		rowsBuf.WriteByte('\n')
+	}
+
+	content := rowsBuf.String()
+	if lines > 0 {
+		content = limitLines(content, lines)
+	}
+	return content, nil
+}
+
+func utf16ToString(buf []uint16) []rune {
+	runes := make([]rune, 0, len(buf))
+	for _, u := range buf {
+		runes = append(runes, rune(u))
+	}
+	return runes
+}
@@ -19,24 +19,26 @@ func runClaude() {
 		baseDir = string(gitRoot[:len(gitRoot)-1]) // remove newline
 	}
 
-	// Create session directory
+	// Create session directory and sweep old sessions past retention
 	now := time.Now()
 	sessionID := now.Format("20060102-150405")
 	sessionDir := filepath.Join(baseDir, ".do", "claude-session", now.Format("2006/01/02"))
-	os.MkdirAll(sessionDir, 0755)
+	sessionBaseDir := filepath.Join(baseDir, ".do", "claude-session")
+	if err := sweepSessionRetention(sessionBaseDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: session retention sweep failed: %v\n", err)
+	}
 
-	logFile := filepath.Join(sessionDir, sessionID+".session")
-	f, err := os.Create(logFile)
+	rec, err := newSessionRecorder(sessionBaseDir, sessionID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: could not create log file: %v\n", err)
 		// Continue without logging
 		runClaudeNoLog()
 		return
 	}
-	defer f.Close()
+	defer rec.Close()
 
 	// Write session start
-	fmt.Fprintf(f, "🎬 Claude session started at %s\n", now.Format(time.RFC3339))
+	rec.WriteEvent("meta", fmt.Sprintf("session started at %s", now.Format(time.RFC3339)))
 	fmt.Fprintf(os.Stderr, "🔗 Session: %s\n", sessionID)
 
 	// Find claude binary
@@ -46,25 +48,38 @@ func runClaude() {
 		os.Exit(1)
 	}
 
-	// Create command with all args
 	args := os.Args[2:] // skip "godo" and "claude"
+
+	// Prefer a PTY-backed run when attached to a real terminal, so claude
+	// sees a TTY (color, interactive prompts, correct line discipline).
+	// Otherwise fall back to the MultiWriter path (pipes, redirected I/O).
+	var exitCode int
+	if supportsPTY() {
+		exitCode = runClaudePTY(claudePath, args, sessionID, rec, sessionDir)
+	} else {
+		exitCode = runClaudeMultiWriter(claudePath, args, sessionID, rec)
+	}
+
+	rec.WriteEvent("meta", fmt.Sprintf("session ended at %s (exit code: %d)", time.Now().Format(time.RFC3339), exitCode))
+
+	os.Exit(exitCode)
+}
+
+// runClaudeMultiWriter runs claude with stdout/stderr mirrored into the
+// structured session log via io.MultiWriter. Used when stdin/stdout aren't
+// a real terminal (pipes, CI, redirected output).
+func runClaudeMultiWriter(claudePath string, args []string, sessionID string, rec *sessionRecorder) int {
 	cmd := exec.Command(claudePath, args...)
 
-	// Set environment variables including session ID
 	cmd.Env = os.Environ()
 	cmd.Env = append(cmd.Env, fmt.Sprintf("CLAUDE_SESSION_ID=%s", sessionID))
 
-	// Connect stdin directly
 	cmd.Stdin = os.Stdin
+	cmd.Stdout = io.MultiWriter(os.Stdout, &sessionStreamWriter{rec: rec, stream: "stdout"})
+	cmd.Stderr = io.MultiWriter(os.Stderr, &sessionStreamWriter{rec: rec, stream: "stderr"})
 
-	// Use MultiWriter to write to both stdout and log file
-	cmd.Stdout = io.MultiWriter(os.Stdout, f)
-	cmd.Stderr = io.MultiWriter(os.Stderr, f)
-
-	// Run claude
-	err = cmd.Run()
+	err := cmd.Run()
 
-	// Write session end
 	exitCode := 0
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -73,10 +88,7 @@ func runClaude() {
 			exitCode = 1
 		}
 	}
-
-	fmt.Fprintf(f, "🏁 Claude session ended at %s (exit code: %d)\n", time.Now().Format(time.RFC3339), exitCode)
-
-	os.Exit(exitCode)
+	return exitCode
 }
 
 func runClaudeNoLog() {
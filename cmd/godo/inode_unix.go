@@ -0,0 +1,16 @@
+//go:build !windows
+
+package main
+
+import "syscall"
+
+// fileInode returns path's inode number, used to detect log rotation (the
+// path now refers to a different underlying file) while following. Returns
+// 0 if the file can't be stat'd.
+func fileInode(path string) uint64 {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(path, &stat); err != nil {
+		return 0
+	}
+	return stat.Ino
+}
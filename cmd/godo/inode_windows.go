@@ -0,0 +1,9 @@
+//go:build windows
+
+package main
+
+// fileInode is a no-op on Windows, which has no stable inode concept
+// exposed here; rotation is instead detected purely by size shrinking.
+func fileInode(path string) uint64 {
+	return 0
+}
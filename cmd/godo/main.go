@@ -4,12 +4,14 @@ import (
 	"archive/tar"
 	"bufio"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"time"
@@ -28,15 +30,27 @@ func main() {
 		os.Exit(1)
 	}
 
+	for _, arg := range os.Args[2:] {
+		if arg == "--silent" || arg == "--no-progress" {
+			silentProgress = true
+		}
+	}
+
 	switch os.Args[1] {
 	case "sync":
 		runSync()
 	case "worker":
 		runWorker()
 	case "selfupdate", "self-update":
-		runSelfUpdate()
+		runSelfUpdate(os.Args[2:])
+	case "--selfupdate-verify":
+		// Hidden flag: selfUpdateDirect execs the freshly-swapped binary with
+		// this before committing to the update, to confirm it actually runs.
+		fmt.Printf("godo version %s\n", version)
 	case "capture":
 		runCapture()
+	case "setup-logging":
+		runSetupLogging()
 	case "version", "-v", "--version":
 		fmt.Printf("godo version %s\n", version)
 	case "help", "-h", "--help":
@@ -56,39 +70,59 @@ Usage:
   godo worker start     Start the memory worker
   godo worker stop      Stop the memory worker
   godo worker status    Show worker status
+  godo worker logs      Stream worker logs (--tail, --follow, --since, --grep)
+  godo worker install   Register the worker as a host service (auto-start on boot)
+  godo worker uninstall Remove the registered worker service
   godo selfupdate       Update godo itself
   godo capture          Capture terminal buffer to file
+  godo setup-logging    Install the claude logging shell wrapper
   godo version          Show version
   godo help             Show this help
 
+Flags:
+  --silent, --no-progress   Suppress download/extraction progress output
+  --channel stable|beta     Selfupdate release channel (default: stable)
+  --allow-downgrade         Allow selfupdate to install an older version
+
 Examples:
   cd my-project
   godo sync                              # Install or update Do
   godo worker start                      # Start memory worker
   godo selfupdate                        # Update godo CLI
-  godo capture --output terminal.txt     # Capture terminal buffer`)
+  godo capture --output terminal.txt     # Capture terminal buffer
+  godo setup-logging --dry-run           # Preview the shell wrapper install
+  godo setup-logging --uninstall         # Remove the shell wrapper`)
 }
 
-func runSelfUpdate() {
+func runSelfUpdate(args []string) {
 	fmt.Println("godo 업데이트 중...")
 	fmt.Printf("현재 버전: %s\n", version)
 
-	// Try brew first
-	cmd := exec.Command("brew", "upgrade", "yejune/tap/godo")
+	pm := detectPackageManager()
+	if pm == nil {
+		fmt.Println("등록된 패키지 매니저 없음. 직접 다운로드 시도...")
+		selfUpdateDirect(args)
+		return
+	}
+
+	fmt.Printf("%s로 업그레이드 시도...\n", pm.name)
+	cmd := pm.command(pm.upgradeArgs)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
 		// Fallback: direct download
-		fmt.Println("brew 업그레이드 실패. 직접 다운로드 시도...")
-		selfUpdateDirect()
+		fmt.Printf("%s 업그레이드 실패. 직접 다운로드 시도...\n", pm.name)
+		selfUpdateDirect(args)
 		return
 	}
 
 	fmt.Println("✓ godo 업데이트 완료!")
 }
 
-func selfUpdateDirect() {
+func selfUpdateDirect(args []string) {
+	channel, allowDowngrade := parseSelfUpdateFlags(args)
+	fmt.Printf("채널: %s\n", channel)
 	// Detect OS and arch
 	goos := os.Getenv("GOOS")
 	goarch := os.Getenv("GOARCH")
@@ -125,44 +159,101 @@ func selfUpdateDirect() {
 	if goos == "windows" {
 		binaryName += ".exe"
 	}
-	url := fmt.Sprintf("https://github.com/yejune/do/releases/latest/download/%s", binaryName)
+	url := fmt.Sprintf("https://github.com/yejune/do/releases/%s/download/%s", releaseChannelPath(channel), binaryName)
 
-	fmt.Printf("다운로드: %s\n", url)
-
-	resp, err := http.Get(url)
+	fmt.Println("매니페스트 확인 중...")
+	manifest, err := fetchManifest(releaseManifestURL(channel))
 	if err != nil {
+		fmt.Printf("오류: release.json 조회 실패: %v\n", err)
+		os.Exit(1)
+	}
+	if err := manifest.verify(); err != nil {
 		fmt.Printf("오류: %v\n", err)
 		os.Exit(1)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		fmt.Printf("오류: HTTP %d\n", resp.StatusCode)
+	if !allowDowngrade && version != "dev" && compareVersions(manifest.Version, version) < 0 {
+		fmt.Printf("오류: 다운그레이드 거부됨 (%s -> %s). --allow-downgrade로 강제 가능.\n", version, manifest.Version)
 		os.Exit(1)
 	}
 
+	fmt.Printf("다운로드: %s\n", url)
+
 	// Get current executable path
 	exePath, err := os.Executable()
 	if err != nil {
 		fmt.Printf("오류: %v\n", err)
 		os.Exit(1)
 	}
+	tmpFile := exePath + ".new"
+	oldFile := exePath + ".old"
+	swapped := false
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+	go func() {
+		if _, ok := <-sigCh; !ok {
+			return
+		}
+		fmt.Println("\n중단됨. 정리 중...")
+		cancel()
+		os.Remove(tmpFile)
+		if swapped {
+			os.Rename(oldFile, exePath)
+		}
+		os.Exit(130)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		fmt.Printf("오류: %v\n", err)
+		os.Exit(1)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		fmt.Printf("오류: %v\n", err)
+		os.Exit(1)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		fmt.Printf("오류: HTTP %d\n", resp.StatusCode)
+		os.Exit(1)
+	}
 
 	// Write to temp file
-	tmpFile := exePath + ".new"
 	f, err := os.OpenFile(tmpFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
 	if err != nil {
 		fmt.Printf("오류: %v\n", err)
 		os.Exit(1)
 	}
 
-	io.Copy(f, resp.Body)
+	pr := newProgressReader(resp.Body, resp.ContentLength, "다운로드")
+	io.Copy(f, pr)
 	f.Close()
 
+	// Fail closed: a manifest without a checksum is not "nothing to verify",
+	// it's an update we can't verify at all, so refuse it rather than
+	// silently installing an unverified binary.
+	if manifest.SHA256 == "" {
+		fmt.Println("오류: 서명된 매니페스트에 SHA256 체크섬이 없습니다")
+		os.Remove(tmpFile)
+		os.Exit(1)
+	}
+	if err := verifyChecksum(tmpFile, manifest.SHA256); err != nil {
+		fmt.Printf("오류: %v\n", err)
+		os.Remove(tmpFile)
+		os.Exit(1)
+	}
+	os.Chmod(tmpFile, 0755)
+
 	// Replace old binary
-	oldFile := exePath + ".old"
 	os.Remove(oldFile)
 	os.Rename(exePath, oldFile)
+	swapped = true
 
 	if err := os.Rename(tmpFile, exePath); err != nil {
 		// Rollback
@@ -171,6 +262,14 @@ func selfUpdateDirect() {
 		os.Exit(1)
 	}
 
+	fmt.Println("상태 확인 중...")
+	if err := verifyNewBinary(exePath); err != nil {
+		fmt.Printf("오류: 새 바이너리 상태 확인 실패 (%v). 이전 버전으로 복구합니다.\n", err)
+		os.Remove(exePath)
+		os.Rename(oldFile, exePath)
+		os.Exit(1)
+	}
+
 	os.Remove(oldFile)
 	fmt.Println("✓ godo 업데이트 완료!")
 }
@@ -289,8 +388,27 @@ func install(force bool) {
 }
 
 func installFromRelease(force bool) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+	go func() {
+		if _, ok := <-sigCh; !ok {
+			return
+		}
+		fmt.Println("\n중단됨.")
+		cancel()
+		os.Exit(130)
+	}()
+
 	// Download tarball
-	resp, err := http.Get(releaseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releaseURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -305,12 +423,16 @@ func installFromRelease(force bool) error {
 	os.MkdirAll(".do/config/sections", 0755)
 
 	// Extract tarball
-	gzr, err := gzip.NewReader(resp.Body)
+	pr := newProgressReader(resp.Body, resp.ContentLength, "다운로드")
+	gzr, err := gzip.NewReader(pr)
 	if err != nil {
 		return err
 	}
 	defer gzr.Close()
 
+	ep := &extractProgress{}
+	defer ep.done()
+
 	tr := tar.NewReader(gzr)
 	for {
 		header, err := tr.Next()
@@ -320,6 +442,7 @@ func installFromRelease(force bool) error {
 		if err != nil {
 			return err
 		}
+		ep.step(header.Name)
 
 		// Skip files that shouldn't be overwritten or extracted
 		target := header.Name
@@ -599,7 +722,7 @@ func registerProject(projectPath string) error {
 // runWorker handles worker subcommands: start, stop, status
 func runWorker() {
 	if len(os.Args) < 3 {
-		fmt.Println("Usage: godo worker [start|stop|status]")
+		fmt.Println("Usage: godo worker [start|stop|status|logs|install|uninstall]")
 		os.Exit(1)
 	}
 
@@ -610,9 +733,15 @@ func runWorker() {
 		workerStop()
 	case "status":
 		workerStatus()
+	case "logs":
+		runWorkerLogs(os.Args[3:])
+	case "install":
+		runWorkerInstall()
+	case "uninstall":
+		runWorkerUninstall()
 	default:
 		fmt.Printf("Unknown worker command: %s\n", os.Args[2])
-		fmt.Println("Usage: godo worker [start|stop|status]")
+		fmt.Println("Usage: godo worker [start|stop|status|logs|install|uninstall]")
 		os.Exit(1)
 	}
 }
@@ -628,7 +757,7 @@ func getWorkerPath() string {
 }
 
 func isWorkerRunning() bool {
-	resp, err := http.Get("http://127.0.0.1:3778/health")
+	resp, err := workerHTTPClient().Get(workerBaseURL() + "/health")
 	if err != nil {
 		return false
 	}
@@ -636,23 +765,45 @@ func isWorkerRunning() bool {
 	return resp.StatusCode == 200
 }
 
+// getWorkerPID reads the PID that workerStart wrote to ~/.do/worker.pid
+// when it launched the worker. lsof isn't installed on most Linux distros by default, so unlike
+// the old port-scanning approach this works everywhere.
 func getWorkerPID() int {
-	// Find worker process by port
-	cmd := exec.Command("lsof", "-ti", ":3778")
-	out, err := cmd.Output()
+	data, err := os.ReadFile(workerPIDPath())
 	if err != nil {
 		return 0
 	}
-	pid := strings.TrimSpace(string(out))
-	if pid == "" {
+	var p int
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(data)), "%d", &p); err != nil {
 		return 0
 	}
-	var p int
-	fmt.Sscanf(pid, "%d", &p)
 	return p
 }
 
+// writeWorkerPID records pid to ~/.do/worker.pid. workerStart calls it
+// right after launching the worker; the worker process does not write this
+// file itself.
+func writeWorkerPID(pid int) error {
+	return os.WriteFile(workerPIDPath(), []byte(fmt.Sprintf("%d\n", pid)), 0644)
+}
+
 func workerStart() {
+	if isServiceInstalled() {
+		if err := serviceControl("start"); err != nil {
+			fmt.Printf("Error: Failed to start service: %v\n", err)
+			os.Exit(1)
+		}
+		for i := 0; i < 10; i++ {
+			time.Sleep(200 * time.Millisecond)
+			if isWorkerRunning() {
+				fmt.Println("✓ Worker started (via service manager)")
+				return
+			}
+		}
+		fmt.Println("Warning: Worker may not have started correctly")
+		return
+	}
+
 	workerPath := getWorkerPath()
 
 	// Check if worker binary exists
@@ -660,39 +811,62 @@ func workerStart() {
 		// Check if it's in PATH (homebrew install)
 		if _, err := exec.LookPath("godo-worker"); err != nil {
 			fmt.Println("Error: godo-worker not found")
-			fmt.Println("       Install with: brew upgrade godo")
+			if pm := detectPackageManager(); pm != nil {
+				fmt.Printf("       Install with: %s %s\n", pm.name, strings.Join(pm.upgradeArgs, " "))
+			} else {
+				fmt.Println("       Install with: brew upgrade godo")
+			}
 			os.Exit(1)
 		}
 	}
 
-	// Kill existing if running (idempotent restart)
-	if pid := getWorkerPID(); pid > 0 {
+	// Kill existing if running (idempotent restart). Nothing removes
+	// worker.pid on SIGTERM, so getWorkerPID() alone can't tell us whether
+	// the process actually died - check the pid's actual liveness instead,
+	// both to decide whether SIGKILL is needed and to avoid ever signaling
+	// an unrelated process that reused a recycled pid.
+	if pid := getWorkerPID(); pid > 0 && processAlive(pid) {
 		// Try SIGTERM first
 		exec.Command("kill", fmt.Sprintf("%d", pid)).Run()
 		time.Sleep(500 * time.Millisecond)
 
-		// If still running, SIGKILL
-		if getWorkerPID() > 0 {
+		// If still alive, SIGKILL
+		if processAlive(pid) {
 			exec.Command("kill", "-9", fmt.Sprintf("%d", pid)).Run()
 			time.Sleep(300 * time.Millisecond)
 		}
 	}
 
-	// Start worker
-	cmd := exec.Command(workerPath)
-	cmd.Stdout = nil
-	cmd.Stderr = nil
+	// Remove a stale socket file left behind by an unclean shutdown, the
+	// same way the worker itself should before ListenUnix.
+	socketPath := workerSocketPath()
+	if info, err := os.Stat(socketPath); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(socketPath)
+	}
+
+	// Start worker, appending its stdout/stderr to ~/.do/logs/worker.log
+	// so `godo worker logs` has something to tail.
+	cmd := exec.Command(workerPath, "--socket", socketPath)
+	if logFile, err := os.OpenFile(workerLogFilePath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err == nil {
+		cmd.Stdout = logFile
+		cmd.Stderr = logFile
+	}
 	if err := cmd.Start(); err != nil {
 		fmt.Printf("Error: Failed to start worker: %v\n", err)
 		os.Exit(1)
 	}
+	writeWorkerPID(cmd.Process.Pid)
 
 	// Wait for startup and verify
 	for i := 0; i < 10; i++ {
 		time.Sleep(200 * time.Millisecond)
 		if isWorkerRunning() {
 			fmt.Printf("✓ Worker started (PID: %d)\n", cmd.Process.Pid)
-			fmt.Println("  http://127.0.0.1:3778")
+			if _, err := os.Stat(socketPath); err == nil {
+				fmt.Printf("  %s\n", socketPath)
+			} else {
+				fmt.Println("  http://127.0.0.1:3778")
+			}
 			return
 		}
 	}
@@ -701,6 +875,15 @@ func workerStart() {
 }
 
 func workerStop() {
+	if isServiceInstalled() {
+		if err := serviceControl("stop"); err != nil {
+			fmt.Printf("Error: Failed to stop service: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ Worker stopped (via service manager)")
+		return
+	}
+
 	pid := getWorkerPID()
 	if pid == 0 {
 		fmt.Println("Worker is not running")
@@ -712,17 +895,27 @@ func workerStop() {
 		os.Exit(1)
 	}
 
+	os.Remove(workerPIDPath())
+	os.Remove(workerSocketPath())
 	fmt.Printf("✓ Worker stopped (PID: %d)\n", pid)
 }
 
 func workerStatus() {
 	if isWorkerRunning() {
-		pid := getWorkerPID()
-		fmt.Printf("✓ Worker is running (PID: %d)\n", pid)
-		fmt.Println("  http://127.0.0.1:3778")
+		fmt.Println("✓ Worker is running")
+		if isServiceInstalled() {
+			fmt.Println("  Managed by: host service manager")
+		} else if pid := getWorkerPID(); pid > 0 {
+			fmt.Printf("  PID: %d\n", pid)
+		}
+		if _, err := os.Stat(workerSocketPath()); err == nil {
+			fmt.Printf("  %s\n", workerSocketPath())
+		} else {
+			fmt.Println("  http://127.0.0.1:3778")
+		}
 
 		// Get health details
-		resp, err := http.Get("http://127.0.0.1:3778/health")
+		resp, err := workerHTTPClient().Get(workerBaseURL() + "/health")
 		if err == nil {
 			defer resp.Body.Close()
 			var health map[string]interface{}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os/exec"
+)
+
+// pkgManager describes how to probe for and drive one system package
+// manager. The same shape covers both "is godo already managed by this
+// tool" (queryArgs) and the upgrade/install commands to run once that's
+// confirmed.
+type pkgManager struct {
+	name        string
+	queryArgs   []string // exits 0 iff godo is registered with this package manager
+	upgradeArgs []string
+	installArgs []string
+	elevate     bool // whether upgrade/install need sudo/doas
+}
+
+// packageManagers lists the managers godo probes, in priority order. brew is
+// checked first since it's what the official tap targets; the rest cover the
+// major Linux distros.
+var packageManagers = []pkgManager{
+	{
+		name:        "brew",
+		queryArgs:   []string{"list", "yejune/tap/godo"},
+		upgradeArgs: []string{"upgrade", "yejune/tap/godo"},
+		installArgs: []string{"install", "yejune/tap/godo"},
+	},
+	{
+		name:        "apt",
+		queryArgs:   []string{"list", "--installed", "godo"},
+		upgradeArgs: []string{"install", "--only-upgrade", "-y", "godo"},
+		installArgs: []string{"install", "-y", "godo"},
+		elevate:     true,
+	},
+	{
+		name:        "dnf",
+		queryArgs:   []string{"list", "installed", "godo"},
+		upgradeArgs: []string{"upgrade", "-y", "godo"},
+		installArgs: []string{"install", "-y", "godo"},
+		elevate:     true,
+	},
+	{
+		name:        "yum",
+		queryArgs:   []string{"list", "installed", "godo"},
+		upgradeArgs: []string{"update", "-y", "godo"},
+		installArgs: []string{"install", "-y", "godo"},
+		elevate:     true,
+	},
+	{
+		name:        "pacman",
+		queryArgs:   []string{"-Qi", "godo"},
+		upgradeArgs: []string{"-S", "--noconfirm", "godo"},
+		installArgs: []string{"-S", "--noconfirm", "godo"},
+		elevate:     true,
+	},
+	{
+		name:        "apk",
+		queryArgs:   []string{"info", "-e", "godo"},
+		upgradeArgs: []string{"add", "-u", "godo"},
+		installArgs: []string{"add", "godo"},
+		elevate:     true,
+	},
+	{
+		name:        "zypper",
+		queryArgs:   []string{"se", "--installed-only", "godo"},
+		upgradeArgs: []string{"update", "-y", "godo"},
+		installArgs: []string{"install", "-y", "godo"},
+		elevate:     true,
+	},
+}
+
+// elevationCommand returns the first privilege-escalation helper found on
+// PATH ("sudo" or "doas"), or "" if neither is available.
+func elevationCommand() string {
+	for _, helper := range []string{"sudo", "doas"} {
+		if _, err := exec.LookPath(helper); err == nil {
+			return helper
+		}
+	}
+	return ""
+}
+
+// detectPackageManager returns the first package manager in priority order
+// that's present on PATH and already has godo registered (its queryArgs
+// succeed), since that's the signal a matching repository is configured.
+func detectPackageManager() *pkgManager {
+	for i := range packageManagers {
+		pm := &packageManagers[i]
+		if _, err := exec.LookPath(pm.name); err != nil {
+			continue
+		}
+		if exec.Command(pm.name, pm.queryArgs...).Run() == nil {
+			return pm
+		}
+	}
+	return nil
+}
+
+// command builds the exec.Cmd for args, prefixing it with sudo/doas when the
+// manager needs elevation.
+func (pm *pkgManager) command(args []string) *exec.Cmd {
+	if !pm.elevate {
+		return exec.Command(pm.name, args...)
+	}
+	if helper := elevationCommand(); helper != "" {
+		return exec.Command(helper, append([]string{pm.name}, args...)...)
+	}
+	return exec.Command(pm.name, args...)
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package main
+
+import "syscall"
+
+// processAlive reports whether pid identifies a running process, via the
+// POSIX convention of sending signal 0: no signal is actually delivered,
+// but the kernel still validates that pid exists and is one we may signal.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	return syscall.Kill(pid, 0) == nil
+}
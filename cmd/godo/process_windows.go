@@ -0,0 +1,15 @@
+//go:build windows
+
+package main
+
+// processAlive reports whether pid identifies a running process. Windows
+// has no signal-0 equivalent, so this reuses the same CreateToolhelp32Snapshot
+// walk parentProcess does (proctree_windows.go) and checks for a matching
+// entry rather than opening a new API surface just for this check.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	_, _, ok := parentProcess(pid)
+	return ok
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// maxProcessTreeWalk bounds how many ancestors processTreeHasAny will climb
+// before giving up, guarding against a parent-chain that loops back on
+// itself due to a platform quirk in parentProcess.
+const maxProcessTreeWalk = 32
+
+// processTreeHasAny reports whether any ancestor of the current process
+// (walking up via the platform-specific parentProcess) has a name
+// containing one of names, case-insensitively. It's used to tell "bare IDE
+// terminal" apart from "IDE terminal that already launched tmux/wezterm/
+// kitty", since $TMUX alone only catches the tmux case.
+func processTreeHasAny(names ...string) bool {
+	pid := os.Getpid()
+	for depth := 0; depth < maxProcessTreeWalk; depth++ {
+		ppid, name, ok := parentProcess(pid)
+		if !ok {
+			return false
+		}
+		lower := strings.ToLower(name)
+		for _, n := range names {
+			if strings.Contains(lower, n) {
+				return true
+			}
+		}
+		if ppid == pid || ppid <= 1 {
+			return false
+		}
+		pid = ppid
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+//go:build darwin || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// parentProcess shells out to `ps -o ppid=,comm= -p <pid>` rather than
+// decoding the sysctl KERN_PROC struct directly: the kinfo_proc layout
+// differs across darwin/freebsd/netbsd/openbsd and isn't worth hand-rolling
+// here when ps already wraps it portably. -o with trailing "=" suppresses
+// the header line so the output is just "<ppid> <comm>".
+func parentProcess(pid int) (ppid int, name string, ok bool) {
+	out, err := exec.Command("ps", "-o", "ppid=,comm=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return 0, "", false
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) < 2 {
+		return 0, "", false
+	}
+	ppid, err = strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, "", false
+	}
+	// comm may itself be a path (e.g. /usr/local/bin/tmux); callers only
+	// match on substring so the full path is fine to return as-is.
+	name = strings.Join(fields[1:], " ")
+	return ppid, name, true
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// parentProcess reads /proc/<pid>/stat to find pid's parent PID and command
+// name. The comm field is parenthesized and can itself contain
+// spaces/parens, so we locate it between the first "(" and the last
+// ")" rather than splitting on whitespace.
+func parentProcess(pid int) (ppid int, name string, ok bool) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, "", false
+	}
+
+	open := strings.IndexByte(string(data), '(')
+	close := strings.LastIndexByte(string(data), ')')
+	if open < 0 || close < 0 || close < open {
+		return 0, "", false
+	}
+	name = string(data)[open+1 : close]
+
+	fields := strings.Fields(string(data)[close+1:])
+	// fields[0] is state, fields[1] is ppid (stat fields after comm start
+	// at field 3, i.e. state).
+	if len(fields) < 2 {
+		return 0, "", false
+	}
+	ppid, err = strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, "", false
+	}
+	return ppid, name, true
+}
@@ -0,0 +1,72 @@
+//go:build windows
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32ProcTree             = syscall.NewLazyDLL("kernel32.dll")
+	procCreateToolhelp32Snapshot = kernel32ProcTree.NewProc("CreateToolhelp32Snapshot")
+	procProcess32FirstW          = kernel32ProcTree.NewProc("Process32FirstW")
+	procProcess32NextW           = kernel32ProcTree.NewProc("Process32NextW")
+	procCloseHandle              = kernel32ProcTree.NewProc("CloseHandle")
+)
+
+const (
+	th32csSnapProcess  = 0x00000002
+	invalidHandleValue = ^uintptr(0)
+	maxPath            = 260
+)
+
+// processEntry32 mirrors the Win32 PROCESSENTRY32W struct; only the fields
+// parentProcess needs are named, the rest just pad out the layout.
+type processEntry32 struct {
+	Size            uint32
+	CntUsage        uint32
+	ProcessID       uint32
+	DefaultHeapID   uintptr
+	ModuleID        uint32
+	CntThreads      uint32
+	ParentProcessID uint32
+	PriClassBase    int32
+	Flags           uint32
+	ExeFile         [maxPath]uint16
+}
+
+// parentProcess walks a full CreateToolhelp32Snapshot of every running
+// process to find pid's entry, since Windows has no per-process "read just
+// this one" query analogous to /proc/<pid>/stat.
+func parentProcess(pid int) (ppid int, name string, ok bool) {
+	snap, _, _ := procCreateToolhelp32Snapshot.Call(uintptr(th32csSnapProcess), 0)
+	if snap == invalidHandleValue || snap == 0 {
+		return 0, "", false
+	}
+	defer procCloseHandle.Call(snap)
+
+	var entry processEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+
+	r, _, _ := procProcess32FirstW.Call(snap, uintptr(unsafe.Pointer(&entry)))
+	for r != 0 {
+		if int(entry.ProcessID) == pid {
+			return int(entry.ParentProcessID), utf16BufToString(entry.ExeFile[:]), true
+		}
+		r, _, _ = procProcess32NextW.Call(snap, uintptr(unsafe.Pointer(&entry)))
+	}
+	return 0, "", false
+}
+
+// utf16BufToString converts a NUL-terminated UTF-16 buffer (as Windows APIs
+// return fixed-size name fields) to a Go string, stopping at the first NUL.
+func utf16BufToString(buf []uint16) string {
+	for i, u := range buf {
+		if u == 0 {
+			buf = buf[:i]
+			break
+		}
+	}
+	return string(utf16ToString(buf))
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// silentProgress disables the progress bar/counter rendering entirely. Set
+// by main() when --silent or --no-progress is passed.
+var silentProgress = false
+
+// progressReader wraps an io.Reader and renders a download progress bar to
+// stderr as bytes flow through its Read method. total is the expected size
+// (Content-Length); 0 or less means unknown and disables the percentage.
+type progressReader struct {
+	r         io.Reader
+	total     int64
+	read      int64
+	label     string
+	start     time.Time
+	lastPrint time.Time
+}
+
+func newProgressReader(r io.Reader, total int64, label string) *progressReader {
+	return &progressReader{r: r, total: total, label: label, start: time.Now()}
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	p.read += int64(n)
+	p.render(err == io.EOF)
+	return n, err
+}
+
+func (p *progressReader) render(final bool) {
+	if silentProgress {
+		return
+	}
+	now := time.Now()
+	if !final && now.Sub(p.lastPrint) < 100*time.Millisecond {
+		return
+	}
+	p.lastPrint = now
+
+	elapsed := now.Sub(p.start).Seconds()
+	speed := float64(p.read)
+	if elapsed > 0 {
+		speed = float64(p.read) / elapsed
+	}
+
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		p.renderBar(speed)
+	} else {
+		p.renderLine(speed)
+	}
+
+	if final {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// renderBar draws an in-place progress bar, overwriting the previous line.
+func (p *progressReader) renderBar(speedBps float64) {
+	if p.total <= 0 {
+		fmt.Fprintf(os.Stderr, "\r%s: %s (%s/s)", p.label, formatBytes(p.read), formatBytes(int64(speedBps)))
+		return
+	}
+	pct := float64(p.read) / float64(p.total) * 100
+	const width = 30
+	filled := int(pct / 100 * width)
+	if filled > width {
+		filled = width
+	}
+	bar := ""
+	for i := 0; i < width; i++ {
+		if i < filled {
+			bar += "="
+		} else {
+			bar += " "
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\r%s: [%s] %5.1f%% %s/%s (%s/s)", p.label, bar, pct, formatBytes(p.read), formatBytes(p.total), formatBytes(int64(speedBps)))
+}
+
+// renderLine prints periodic percentage lines for non-TTY stderr (logs,
+// CI output) instead of overwriting an in-place bar.
+func (p *progressReader) renderLine(speedBps float64) {
+	if p.total <= 0 {
+		fmt.Fprintf(os.Stderr, "%s: %s downloaded (%s/s)\n", p.label, formatBytes(p.read), formatBytes(int64(speedBps)))
+		return
+	}
+	pct := float64(p.read) / float64(p.total) * 100
+	fmt.Fprintf(os.Stderr, "%s: %.0f%% (%s/%s)\n", p.label, pct, formatBytes(p.read), formatBytes(p.total))
+}
+
+// extractProgress reports tar extraction progress as a running entry
+// counter. It has no notion of total entries up front since the tar stream
+// is read sequentially, so it just reports "N files" with the current path.
+type extractProgress struct {
+	count     int
+	lastPrint time.Time
+}
+
+func (e *extractProgress) step(path string) {
+	e.count++
+	if silentProgress {
+		return
+	}
+	now := time.Now()
+	if now.Sub(e.lastPrint) < 100*time.Millisecond {
+		return
+	}
+	e.lastPrint = now
+
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		fmt.Fprintf(os.Stderr, "\r압축 해제 중: %d개 파일 (%s)", e.count, path)
+	} else {
+		fmt.Fprintf(os.Stderr, "압축 해제 중: %d개 파일 (%s)\n", e.count, path)
+	}
+}
+
+func (e *extractProgress) done() {
+	if silentProgress {
+		return
+	}
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
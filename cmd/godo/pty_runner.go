@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/creack/pty"
+	"golang.org/x/term"
+)
+
+// runClaudePTY runs claude attached to a pseudo-terminal so it sees a real
+// TTY (color, interactive prompts, correct line discipline) instead of the
+// pipe-backed MultiWriter path. Alongside the structured JSONL session log
+// it writes a script(1)-style typescript plus a timing file so the session
+// can be replayed with `scriptreplay`.
+func runClaudePTY(claudePath string, args []string, sessionID string, rec *sessionRecorder, sessionDir string) int {
+	cmd := exec.Command(claudePath, args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("CLAUDE_SESSION_ID=%s", sessionID))
+
+	ptmx, err := pty.Start(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: pty start failed, falling back: %v\n", err)
+		return runClaudeMultiWriter(claudePath, args, sessionID, rec)
+	}
+	defer ptmx.Close()
+
+	// Forward terminal resizes to the child pty.
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+	go func() {
+		for range winch {
+			pty.InheritSize(os.Stdin, ptmx)
+		}
+	}()
+	winch <- syscall.SIGWINCH // initial size
+
+	// Put the real stdin into raw mode and restore it on exit.
+	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err == nil {
+		defer term.Restore(int(os.Stdin.Fd()), oldState)
+	}
+
+	typescriptPath := filepath.Join(sessionDir, sessionID+".typescript")
+	timingPath := filepath.Join(sessionDir, sessionID+".timing")
+
+	tsFile, err := os.Create(typescriptPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not create typescript file: %v\n", err)
+	} else {
+		defer tsFile.Close()
+	}
+
+	timingFile, err := os.Create(timingPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not create timing file: %v\n", err)
+	} else {
+		defer timingFile.Close()
+	}
+
+	go io.Copy(ptmx, os.Stdin)
+
+	last := time.Now()
+	buf := make([]byte, 4096)
+	for {
+		n, readErr := ptmx.Read(buf)
+		if n > 0 {
+			chunk := buf[:n]
+			os.Stdout.Write(chunk)
+			if tsFile != nil {
+				tsFile.Write(chunk)
+			}
+			if timingFile != nil {
+				now := time.Now()
+				fmt.Fprintf(timingFile, "%.6f %d\n", now.Sub(last).Seconds(), n)
+				last = now
+			}
+			rec.WriteEvent("stdout", string(chunk))
+		}
+		if readErr != nil {
+			break
+		}
+	}
+
+	exitCode := 0
+	if waitErr := cmd.Wait(); waitErr != nil {
+		if exitErr, ok := waitErr.(*exec.ExitError); ok {
+			exitCode = exitErr.ExitCode()
+		} else {
+			exitCode = 1
+		}
+	}
+	return exitCode
+}
+
+// supportsPTY reports whether both stdin and stdout are connected to a real
+// terminal, making the PTY-backed runner worthwhile.
+func supportsPTY() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+)
+
+const serviceName = "godo-worker"
+
+// runWorkerInstall implements `godo worker install`: register the worker as
+// a host service (systemd user unit / launchd agent / scheduled task) so it
+// survives logout and restarts on failure, instead of dying with the
+// terminal that launched `godo worker start`. The Windows scheduled task
+// only starts the worker at logon; it is not restarted on failure.
+func runWorkerInstall() {
+	if isServiceInstalled() {
+		fmt.Println("이미 서비스로 등록됨.")
+		return
+	}
+
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		err = installSystemdService()
+	case "darwin":
+		err = installLaunchdService()
+	case "windows":
+		err = installWindowsService()
+	default:
+		err = fmt.Errorf("지원하지 않는 플랫폼: %s", runtime.GOOS)
+	}
+	if err != nil {
+		fmt.Printf("오류: 서비스 등록 실패: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("✓ godo-worker가 서비스로 등록되어 부팅 시 자동 시작됩니다")
+}
+
+// runWorkerUninstall implements `godo worker uninstall`: stop and remove the
+// registered service, leaving plain `godo worker start`/`stop` in charge
+// again.
+func runWorkerUninstall() {
+	if !isServiceInstalled() {
+		fmt.Println("서비스로 등록되어 있지 않음.")
+		return
+	}
+
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		err = uninstallSystemdService()
+	case "darwin":
+		err = uninstallLaunchdService()
+	case "windows":
+		err = uninstallWindowsService()
+	default:
+		err = fmt.Errorf("지원하지 않는 플랫폼: %s", runtime.GOOS)
+	}
+	if err != nil {
+		fmt.Printf("오류: 서비스 제거 실패: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("✓ godo-worker 서비스 제거됨")
+}
+
+// isServiceInstalled reports whether the worker is registered with the
+// host's service manager, so workerStart/workerStop/workerStatus can
+// delegate to it instead of managing a bare PID and fighting the service
+// manager's own restart logic.
+func isServiceInstalled() bool {
+	switch runtime.GOOS {
+	case "linux":
+		return fileExists(systemdUnitPath())
+	case "darwin":
+		return fileExists(launchdPlistPath())
+	case "windows":
+		return exec.Command("schtasks", "/query", "/tn", windowsTaskName).Run() == nil
+	default:
+		return false
+	}
+}
+
+// serviceControl runs the host service manager's start or stop verb for
+// godo-worker. On Linux action is passed to systemctl as-is; launchd and
+// Windows accept only "start" and "stop". Returns an error if the command itself couldn't run;
+// callers still confirm the effect via isWorkerRunning().
+func serviceControl(action string) error {
+	switch runtime.GOOS {
+	case "linux":
+		return exec.Command("systemctl", "--user", action, serviceName+".service").Run()
+	case "darwin":
+		label := launchdLabel()
+		switch action {
+		case "start":
+			return exec.Command("launchctl", "kickstart", "-k", launchdTarget(label)).Run()
+		case "stop":
+			return exec.Command("launchctl", "kill", "SIGTERM", launchdTarget(label)).Run()
+		default:
+			return fmt.Errorf("unsupported action: %s", action)
+		}
+	case "windows":
+		switch action {
+		case "start":
+			return exec.Command("schtasks", "/run", "/tn", windowsTaskName).Run()
+		case "stop":
+			return exec.Command("schtasks", "/end", "/tn", windowsTaskName).Run()
+		default:
+			return fmt.Errorf("unsupported action: %s", action)
+		}
+	default:
+		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	}
+}
+
+// --- systemd (Linux) ---
+
+func systemdUnitPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".config", "systemd", "user", serviceName+".service")
+}
+
+func installSystemdService() error {
+	unitPath := systemdUnitPath()
+	if err := os.MkdirAll(filepath.Dir(unitPath), 0755); err != nil {
+		return err
+	}
+
+	logPath := workerLogFilePath()
+	var env string
+	for _, kv := range loadConfigEnv() {
+		env += fmt.Sprintf("Environment=%s\n", kv)
+	}
+
+	unit := fmt.Sprintf(`[Unit]
+Description=godo worker - Do memory service
+After=network.target
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+RestartSec=2
+StandardOutput=append:%s
+StandardError=append:%s
+%s
+[Install]
+WantedBy=default.target
+`, getWorkerPath(), logPath, logPath, env)
+
+	if err := os.WriteFile(unitPath, []byte(unit), 0644); err != nil {
+		return err
+	}
+
+	if err := exec.Command("systemctl", "--user", "daemon-reload").Run(); err != nil {
+		return err
+	}
+	return exec.Command("systemctl", "--user", "enable", "--now", serviceName+".service").Run()
+}
+
+func uninstallSystemdService() error {
+	exec.Command("systemctl", "--user", "disable", "--now", serviceName+".service").Run()
+	return os.Remove(systemdUnitPath())
+}
+
+// --- launchd (macOS) ---
+
+func launchdLabel() string {
+	return "dev.godo.worker"
+}
+
+func launchdTarget(label string) string {
+	return fmt.Sprintf("gui/%d/%s", os.Getuid(), label)
+}
+
+func launchdPlistPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, "Library", "LaunchAgents", launchdLabel()+".plist")
+}
+
+func installLaunchdService() error {
+	plistPath := launchdPlistPath()
+	if err := os.MkdirAll(filepath.Dir(plistPath), 0755); err != nil {
+		return err
+	}
+
+	logPath := workerLogFilePath()
+	var envXML string
+	envVars := loadConfigEnv()
+	if len(envVars) > 0 {
+		envXML = "\t<key>EnvironmentVariables</key>\n\t<dict>\n"
+		for _, kv := range envVars {
+			key, value := splitEnvKV(kv)
+			envXML += fmt.Sprintf("\t\t<key>%s</key>\n\t\t<string>%s</string>\n", key, value)
+		}
+		envXML += "\t</dict>\n"
+	}
+
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+%s</dict>
+</plist>
+`, launchdLabel(), getWorkerPath(), logPath, logPath, envXML)
+
+	if err := os.WriteFile(plistPath, []byte(plist), 0644); err != nil {
+		return err
+	}
+
+	target := launchdTarget(launchdLabel())
+	gui := fmt.Sprintf("gui/%d", os.Getuid())
+	exec.Command("launchctl", "bootout", target).Run()
+	if err := exec.Command("launchctl", "bootstrap", gui, plistPath).Run(); err != nil {
+		return err
+	}
+	return exec.Command("launchctl", "enable", target).Run()
+}
+
+func uninstallLaunchdService() error {
+	exec.Command("launchctl", "bootout", launchdTarget(launchdLabel())).Run()
+	return os.Remove(launchdPlistPath())
+}
+
+// --- Windows ---
+
+const windowsTaskName = "GodoWorker"
+
+func installWindowsService() error {
+	cmd := exec.Command("schtasks", "/create",
+		"/tn", windowsTaskName,
+		"/tr", getWorkerPath(),
+		"/sc", "onlogon",
+		"/rl", "highest",
+		"/f")
+	return cmd.Run()
+}
+
+func uninstallWindowsService() error {
+	return exec.Command("schtasks", "/delete", "/tn", windowsTaskName, "/f").Run()
+}
+
+// --- shared ---
+
+// loadConfigEnv flattens ~/.do/config.json into "DO_SECTION_KEY=value" env
+// assignments (e.g. worker.port -> DO_WORKER_PORT=3778), so the service
+// unit sees the same settings the CLI reads.
+func loadConfigEnv() []string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(homeDir, ".do", "config.json"))
+	if err != nil {
+		return nil
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+
+	var env []string
+	flattenConfigEnv("DO", raw, &env)
+	sort.Strings(env)
+	return env
+}
+
+func flattenConfigEnv(prefix string, value interface{}, env *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flattenConfigEnv(prefix+"_"+envKey(key), child, env)
+		}
+	case string:
+		*env = append(*env, fmt.Sprintf("%s=%s", prefix, v))
+	case float64:
+		*env = append(*env, fmt.Sprintf("%s=%s", prefix, strconv.FormatFloat(v, 'f', -1, 64)))
+	case bool:
+		*env = append(*env, fmt.Sprintf("%s=%t", prefix, v))
+	}
+}
+
+func envKey(key string) string {
+	out := make([]byte, len(key))
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		out[i] = c
+	}
+	return string(out)
+}
+
+func splitEnvKV(kv string) (key, value string) {
+	for i := 0; i < len(kv); i++ {
+		if kv[i] == '=' {
+			return kv[:i], kv[i+1:]
+		}
+	}
+	return kv, ""
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sessionEvent is a single structured log line written to a session's JSONL
+// file. One line is emitted per Write() call on stdout/stderr, plus "meta"
+// events for session start/end.
+type sessionEvent struct {
+	TS        string `json:"ts"`
+	SessionID string `json:"session_id"`
+	Stream    string `json:"stream"` // stdout|stderr|meta
+	Seq       int64  `json:"seq"`
+	Data      string `json:"data"`
+}
+
+// defaultSessionMaxBytes is the size at which a session log rolls to a new
+// suffixed file, overridable via DO_SESSION_MAX_BYTES.
+const defaultSessionMaxBytes = 10 * 1024 * 1024 // 10MB
+
+// defaultSessionRetentionDays is how long rotated session logs are kept
+// before the retention sweep deletes them, overridable via
+// DO_SESSION_RETENTION_DAYS.
+const defaultSessionRetentionDays = 30
+
+// sessionRecorder writes structured JSONL events for a single Claude
+// session, rotating to a new suffixed file when the current one exceeds
+// maxBytes or when the date rolls over.
+type sessionRecorder struct {
+	mu sync.Mutex
+
+	baseDir   string // .do/claude-session
+	sessionID string
+	maxBytes  int64
+
+	date    string // YYYY/MM/DD for the currently open file
+	suffix  int    // rotation suffix within the current date
+	file    *os.File
+	size    int64
+	seq     int64
+}
+
+// newSessionRecorder creates a recorder and opens the first log file under
+// baseDir/YYYY/MM/DD/<sessionID>[.N].jsonl.
+func newSessionRecorder(baseDir, sessionID string) (*sessionRecorder, error) {
+	r := &sessionRecorder{
+		baseDir:   baseDir,
+		sessionID: sessionID,
+		maxBytes:  sessionMaxBytes(),
+	}
+	if err := r.rotate(time.Now()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func sessionMaxBytes() int64 {
+	if v := os.Getenv("DO_SESSION_MAX_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultSessionMaxBytes
+}
+
+func sessionRetentionDays() int {
+	if v := os.Getenv("DO_SESSION_RETENTION_DAYS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultSessionRetentionDays
+}
+
+// rotate closes the current file (if any) and opens a new one for the given
+// timestamp's date, picking the next available suffix.
+func (r *sessionRecorder) rotate(now time.Time) error {
+	if r.file != nil {
+		r.file.Close()
+	}
+
+	date := now.Format("2006/01/02")
+	if date != r.date {
+		r.date = date
+		r.suffix = 0
+	} else {
+		r.suffix++
+	}
+
+	dir := filepath.Join(r.baseDir, r.date)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create session log dir: %w", err)
+	}
+
+	name := r.sessionID + ".jsonl"
+	if r.suffix > 0 {
+		name = fmt.Sprintf("%s.%d.jsonl", r.sessionID, r.suffix)
+	}
+	path := filepath.Join(dir, name)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("create session log file: %w", err)
+	}
+
+	r.file = f
+	r.size = 0
+	return nil
+}
+
+// WriteEvent appends a structured event, rotating first if the current file
+// has grown past maxBytes or the date has rolled over.
+func (r *sessionRecorder) WriteEvent(stream, data string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Format("2006/01/02") != r.date || r.size >= r.maxBytes {
+		if err := r.rotate(now); err != nil {
+			return err
+		}
+	}
+
+	r.seq++
+	line, err := json.Marshal(sessionEvent{
+		TS:        now.Format(time.RFC3339Nano),
+		SessionID: r.sessionID,
+		Stream:    stream,
+		Seq:       r.seq,
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+	return err
+}
+
+// Close closes the currently open log file.
+func (r *sessionRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
+
+// sessionStreamWriter adapts a sessionRecorder to io.Writer for use with
+// io.MultiWriter against a specific stream (stdout/stderr).
+type sessionStreamWriter struct {
+	rec    *sessionRecorder
+	stream string
+}
+
+func (w *sessionStreamWriter) Write(p []byte) (int, error) {
+	if err := w.rec.WriteEvent(w.stream, string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// sweepSessionRetention removes dated session-log directories under baseDir
+// (<base>/YYYY/MM/DD/) older than the configured retention window. It is
+// run once on startup so disk usage stays bounded regardless of how many
+// sessions have accumulated.
+func sweepSessionRetention(baseDir string) error {
+	cutoff := time.Now().AddDate(0, 0, -sessionRetentionDays())
+
+	years, err := os.ReadDir(baseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, y := range years {
+		if !y.IsDir() {
+			continue
+		}
+		months, err := os.ReadDir(filepath.Join(baseDir, y.Name()))
+		if err != nil {
+			continue
+		}
+		for _, m := range months {
+			if !m.IsDir() {
+				continue
+			}
+			dayDir := filepath.Join(baseDir, y.Name(), m.Name())
+			days, err := os.ReadDir(dayDir)
+			if err != nil {
+				continue
+			}
+			for _, d := range days {
+				if !d.IsDir() {
+					continue
+				}
+				date, err := time.Parse("2006/01/02", filepath.Join(y.Name(), m.Name(), d.Name()))
+				if err != nil {
+					continue
+				}
+				if date.Before(cutoff) {
+					os.RemoveAll(filepath.Join(dayDir, d.Name()))
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runWorkerLogs implements `godo worker logs`, mirroring `kubectl logs -f`:
+// stream the worker's log over HTTP/unix when it's reachable, falling back
+// to tailing the log file on disk directly.
+func runWorkerLogs(args []string) {
+	tail := 200
+	follow := false
+	var since time.Duration
+	var grep string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--tail":
+			i++
+			if i < len(args) {
+				if n, err := strconv.Atoi(args[i]); err == nil {
+					tail = n
+				}
+			}
+		case "--follow", "-f":
+			follow = true
+		case "--since":
+			i++
+			if i < len(args) {
+				if d, err := time.ParseDuration(args[i]); err == nil {
+					since = d
+				}
+			}
+		case "--grep":
+			i++
+			if i < len(args) {
+				grep = args[i]
+			}
+		}
+	}
+
+	var matcher *regexp.Regexp
+	if grep != "" {
+		m, err := regexp.Compile(grep)
+		if err != nil {
+			fmt.Printf("Error: invalid --grep pattern: %v\n", err)
+			os.Exit(1)
+		}
+		matcher = m
+	}
+
+	var cutoff time.Time
+	if since > 0 {
+		cutoff = time.Now().Add(-since)
+	}
+
+	if streamRemoteLogs(tail, follow, grep, matcher, cutoff) {
+		return
+	}
+
+	tailLogFile(workerLogFilePath(), tail, follow, matcher, cutoff)
+}
+
+// workerLogFilePath mirrors internal/server.workerLogPath: the file the
+// worker appends its own stdout/stderr to.
+func workerLogFilePath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".do", "logs", "worker.log")
+}
+
+// streamRemoteLogs requests GET /api/logs from the running worker (over its
+// Unix socket or TCP, whichever is active) and streams the response to
+// stdout. Returns false if the worker isn't reachable or the endpoint
+// doesn't exist, so the caller can fall back to tailing the file directly.
+func streamRemoteLogs(tail int, follow bool, grep string, matcher *regexp.Regexp, cutoff time.Time) bool {
+	if !isWorkerRunning() {
+		return false
+	}
+
+	q := url.Values{}
+	q.Set("tail", strconv.Itoa(tail))
+	if follow {
+		q.Set("follow", "1")
+	}
+	if grep != "" {
+		q.Set("grep", grep)
+	}
+
+	client := *workerHTTPClient()
+	if follow {
+		// A followed stream stays open indefinitely, so drop the client's
+		// overall request timeout.
+		client.Timeout = 0
+	}
+	resp, err := client.Get(workerBaseURL() + "/api/logs?" + q.Encode())
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return false
+	}
+	if resp.StatusCode != 200 {
+		return false
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !lineMatchesSince(line, cutoff) {
+			continue
+		}
+		fmt.Println(line)
+	}
+	return true
+}
+
+// tailLogFile reads the last n lines of path by seeking from EOF and
+// scanning backward for newlines, then optionally follows the file for new
+// writes, polling via os.Stat and re-opening when the inode changes
+// (logrotate-style rotation).
+func tailLogFile(path string, n int, follow bool, matcher *regexp.Regexp, cutoff time.Time) {
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("Error: worker is not running and no log file at %s\n", path)
+		os.Exit(1)
+	}
+
+	print := func(line string) {
+		if matcher != nil && !matcher.MatchString(line) {
+			return
+		}
+		if !lineMatchesSince(line, cutoff) {
+			return
+		}
+		fmt.Println(line)
+	}
+
+	for _, line := range lastLines(f, n) {
+		print(line)
+	}
+
+	if !follow {
+		f.Close()
+		return
+	}
+
+	offset, _ := f.Seek(0, io.SeekEnd)
+	inode := fileInode(path)
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for range ticker.C {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if newInode := fileInode(path); newInode != inode && newInode != 0 {
+			f.Close()
+			if f, err = os.Open(path); err != nil {
+				continue
+			}
+			inode = newInode
+			offset = 0
+		}
+		if info.Size() < offset {
+			offset = 0
+		}
+		if info.Size() == offset {
+			continue
+		}
+		f.Seek(offset, io.SeekStart)
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			print(scanner.Text())
+		}
+		offset, _ = f.Seek(0, io.SeekCurrent)
+	}
+}
+
+// lastLines returns the last n lines of f, seeking from the end in chunks
+// and scanning backward for newlines instead of reading the whole file.
+func lastLines(f *os.File, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return nil
+	}
+	size := info.Size()
+
+	const chunkSize = 4096
+	var (
+		pos       = size
+		newlines  = 0
+		buf       = make([]byte, chunkSize)
+		collected []byte
+	)
+	for pos > 0 && newlines <= n {
+		readSize := int64(chunkSize)
+		if pos < readSize {
+			readSize = pos
+		}
+		pos -= readSize
+
+		if _, err := f.ReadAt(buf[:readSize], pos); err != nil {
+			break
+		}
+		chunk := make([]byte, readSize, readSize+int64(len(collected)))
+		copy(chunk, buf[:readSize])
+		collected = append(chunk, collected...)
+		for _, b := range buf[:readSize] {
+			if b == '\n' {
+				newlines++
+			}
+		}
+	}
+
+	lines := strings.Split(string(collected), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// lineMatchesSince reports whether line's leading RFC3339 timestamp (if any)
+// is at or after cutoff. Lines without a parseable timestamp, or a zero
+// cutoff (no --since given), always pass.
+func lineMatchesSince(line string, cutoff time.Time) bool {
+	if cutoff.IsZero() {
+		return true
+	}
+	fields := strings.SplitN(line, " ", 2)
+	if len(fields) == 0 {
+		return true
+	}
+	ts, err := time.Parse(time.RFC3339, fields[0])
+	if err != nil {
+		return true
+	}
+	return !ts.Before(cutoff)
+}
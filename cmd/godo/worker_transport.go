@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// workerSocketPath returns ~/.do/worker.sock, the default Unix domain
+// socket the worker listens on instead of (or alongside) TCP 3778.
+func workerSocketPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".do", "worker.sock")
+}
+
+// workerPIDPath returns ~/.do/worker.pid, written by workerStart right after
+// it launches the worker, so the CLI doesn't have to shell out to lsof
+// (which isn't available on Linux by default) to find its PID.
+func workerPIDPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".do", "worker.pid")
+}
+
+// workerHTTPClient returns an http.Client that dials the worker over its
+// Unix socket when one exists, falling back to plain TCP otherwise. The
+// "http://unix-socket" host from workerBaseURL is a placeholder; DialContext ignores it and always
+// dials the socket path.
+func workerHTTPClient() *http.Client {
+	socketPath := workerSocketPath()
+	if _, err := os.Stat(socketPath); err != nil {
+		return http.DefaultClient
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+		Timeout: 5 * time.Second,
+	}
+}
+
+// workerBaseURL returns the base URL to issue worker requests against. When
+// the Unix socket exists, the host/port are ignored by workerHTTPClient's
+// DialContext, but net/http still requires a well-formed URL.
+func workerBaseURL() string {
+	if _, err := os.Stat(workerSocketPath()); err == nil {
+		return "http://unix-socket"
+	}
+	return "http://127.0.0.1:3778"
+}
@@ -0,0 +1,89 @@
+// Package ansi tokenizes SGR/CSI escape sequences in captured terminal
+// output, so the capture command's --format flag can render the same
+// captured bytes as plain text, colorized HTML, or structured JSON lines
+// without re-parsing escape codes separately for each output format.
+package ansi
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TokenKind distinguishes a literal text run from an SGR style change.
+type TokenKind int
+
+const (
+	TokenText TokenKind = iota
+	TokenStyle
+)
+
+// Token is either a run of literal text (Kind == TokenText) or an SGR
+// parameter list that changes style from this point on (Kind == TokenStyle).
+type Token struct {
+	Kind  TokenKind
+	Text  string
+	Codes []int // SGR parameters, e.g. [1, 31] for bold red.
+}
+
+// csiRe matches any CSI sequence (ESC [, an optional private-mode prefix,
+// params, intermediate bytes, final byte). Only the "m" (SGR) form becomes
+// a TokenStyle; everything else (cursor moves, erase, etc.) carries no
+// meaning once the buffer is captured as flat text, so it's dropped.
+var csiRe = regexp.MustCompile("\x1b\\[[<=>?]?([0-9;]*)[ -/]*([@-~])")
+
+// oscRe matches OSC sequences (ESC ] ... BEL or ESC ] ... ST), used for
+// things like setting the terminal title; captured output has no use for
+// these either.
+var oscRe = regexp.MustCompile("\x1b\\].*?(\x07|\x1b\\\\)")
+
+// Tokenize splits s into an ordered sequence of Tokens, preserving exactly
+// the text that would remain visible once escape sequences are removed.
+func Tokenize(s string) []Token {
+	s = oscRe.ReplaceAllString(s, "")
+
+	var tokens []Token
+	last := 0
+	for _, loc := range csiRe.FindAllStringSubmatchIndex(s, -1) {
+		start, end := loc[0], loc[1]
+		if start > last {
+			tokens = append(tokens, Token{Kind: TokenText, Text: s[last:start]})
+		}
+		if s[loc[4]:loc[5]] == "m" {
+			tokens = append(tokens, Token{Kind: TokenStyle, Codes: parseSGR(s[loc[2]:loc[3]])})
+		}
+		last = end
+	}
+	if last < len(s) {
+		tokens = append(tokens, Token{Kind: TokenText, Text: s[last:]})
+	}
+	return tokens
+}
+
+func parseSGR(params string) []int {
+	if params == "" {
+		return []int{0}
+	}
+	parts := strings.Split(params, ";")
+	codes := make([]int, 0, len(parts))
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			n = 0
+		}
+		codes = append(codes, n)
+	}
+	return codes
+}
+
+// Strip returns s with all CSI/OSC escape sequences removed, leaving only
+// the visible text. This backs capture's --format plain.
+func Strip(s string) string {
+	var sb strings.Builder
+	for _, t := range Tokenize(s) {
+		if t.Kind == TokenText {
+			sb.WriteString(t.Text)
+		}
+	}
+	return sb.String()
+}
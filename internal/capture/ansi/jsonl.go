@@ -0,0 +1,46 @@
+package ansi
+
+import "strings"
+
+// Line is one line of JSONL output for capture's --format jsonl: the
+// visible text and the SGR codes applied up to and including that line.
+// Ts is left zero unless the caller has an external per-line timestamp
+// source (e.g. tmux capture-pane -e preserves escape sequences but not a
+// per-line clock by itself, so callers that want real timestamps need to
+// supply them, such as from a terminal log that interleaves one).
+type Line struct {
+	Ts    int64  `json:"ts,omitempty"`
+	Text  string `json:"text"`
+	Style []int  `json:"style,omitempty"`
+}
+
+// JSONLines splits s into one Line per newline, each carrying its plain
+// text and the cumulative raw SGR codes applied up to that point.
+func JSONLines(s string) []Line {
+	var lines []Line
+	var cur style
+	var buf strings.Builder
+
+	flush := func() {
+		lines = append(lines, Line{Text: buf.String(), Style: append([]int{}, cur.raw...)})
+		buf.Reset()
+	}
+
+	for _, t := range Tokenize(s) {
+		if t.Kind == TokenStyle {
+			cur.apply(t.Codes)
+			continue
+		}
+		for _, r := range t.Text {
+			if r == '\n' {
+				flush()
+				continue
+			}
+			buf.WriteRune(r)
+		}
+	}
+	if buf.Len() > 0 {
+		flush()
+	}
+	return lines
+}
@@ -0,0 +1,186 @@
+package ansi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ansi16 maps SGR 30-37/40-47 to the standard 16-color palette's CSS hex
+// values (the common xterm defaults, since the real palette is terminal-
+// theme-dependent and unrecoverable from the escape codes alone).
+var ansi16 = [8]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00",
+	"#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+}
+
+// ansi16Bright maps SGR 90-97/100-107 to the bright variants.
+var ansi16Bright = [8]string{
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00",
+	"#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// style tracks the SGR attributes currently in effect while rendering.
+type style struct {
+	bold, italic, underline bool
+	fg, bg                  string // CSS color, "" if not set
+	raw                     []int  // every code applied since the last reset, for JSONLines
+}
+
+// apply updates st from one SGR parameter list (everything between ESC [
+// and the trailing "m").
+func (st *style) apply(codes []int) {
+	if len(codes) == 1 && codes[0] == 0 {
+		*st = style{}
+		return
+	}
+	st.raw = append(st.raw, codes...)
+
+	for i := 0; i < len(codes); i++ {
+		c := codes[i]
+		switch {
+		case c == 0:
+			*st = style{raw: append([]int{}, codes[i:]...)}
+		case c == 1:
+			st.bold = true
+		case c == 22:
+			st.bold = false
+		case c == 3:
+			st.italic = true
+		case c == 23:
+			st.italic = false
+		case c == 4:
+			st.underline = true
+		case c == 24:
+			st.underline = false
+		case c >= 30 && c <= 37:
+			st.fg = ansi16[c-30]
+		case c == 39:
+			st.fg = ""
+		case c >= 40 && c <= 47:
+			st.bg = ansi16[c-40]
+		case c == 49:
+			st.bg = ""
+		case c >= 90 && c <= 97:
+			st.fg = ansi16Bright[c-90]
+		case c >= 100 && c <= 107:
+			st.bg = ansi16Bright[c-100]
+		case c == 38 || c == 48:
+			color, consumed := extendedColor(codes[i+1:])
+			if color != "" {
+				if c == 38 {
+					st.fg = color
+				} else {
+					st.bg = color
+				}
+			}
+			i += consumed
+		}
+	}
+}
+
+// extendedColor parses the parameter(s) following an SGR 38/48 (set
+// foreground/background), supporting both the 256-color form
+// (5;<0-255>) and the truecolor form (2;r;g;b). It returns the CSS color
+// and how many entries of rest it consumed.
+func extendedColor(rest []int) (string, int) {
+	if len(rest) == 0 {
+		return "", 0
+	}
+	switch rest[0] {
+	case 5:
+		if len(rest) < 2 {
+			return "", 1
+		}
+		return palette256(rest[1]), 2
+	case 2:
+		if len(rest) < 4 {
+			return "", 1
+		}
+		return fmt.Sprintf("#%02x%02x%02x", rest[1], rest[2], rest[3]), 4
+	default:
+		return "", 1
+	}
+}
+
+// palette256 converts an xterm 256-color index to a CSS hex color: 0-15
+// are the standard/bright 16 colors, 16-231 are a 6x6x6 RGB cube, and
+// 232-255 are a 24-step grayscale ramp.
+func palette256(n int) string {
+	switch {
+	case n < 8:
+		return ansi16[n]
+	case n < 16:
+		return ansi16Bright[n-8]
+	case n < 232:
+		n -= 16
+		r, g, b := (n/36)%6, (n/6)%6, n%6
+		scale := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return fmt.Sprintf("#%02x%02x%02x", scale(r), scale(g), scale(b))
+	default:
+		gray := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+}
+
+// css renders the current style as an inline CSS declaration list, or ""
+// if no attribute is set (in which case HTML skips wrapping the run in a
+// span at all).
+func (st style) css() string {
+	var parts []string
+	if st.bold {
+		parts = append(parts, "font-weight:bold")
+	}
+	if st.italic {
+		parts = append(parts, "font-style:italic")
+	}
+	if st.underline {
+		parts = append(parts, "text-decoration:underline")
+	}
+	if st.fg != "" {
+		parts = append(parts, "color:"+st.fg)
+	}
+	if st.bg != "" {
+		parts = append(parts, "background-color:"+st.bg)
+	}
+	return strings.Join(parts, ";")
+}
+
+// HTML renders s as an HTML fragment, wrapping each run that has a
+// non-default style in a <span style="..."> honoring bold/italic/
+// underline, the 16 standard colors, 256-color (38/48;5;N), and truecolor
+// (38/48;2;r;g;b) SGR sequences. Text is HTML-escaped first so captured
+// output can't inject markup.
+func HTML(s string) string {
+	var sb strings.Builder
+	var cur style
+	for _, t := range Tokenize(s) {
+		switch t.Kind {
+		case TokenStyle:
+			cur.apply(t.Codes)
+		case TokenText:
+			escaped := htmlEscape(t.Text)
+			if css := cur.css(); css != "" {
+				sb.WriteString(`<span style="`)
+				sb.WriteString(css)
+				sb.WriteString(`">`)
+				sb.WriteString(escaped)
+				sb.WriteString(`</span>`)
+			} else {
+				sb.WriteString(escaped)
+			}
+		}
+	}
+	return sb.String()
+}
+
+func htmlEscape(s string) string {
+	s = strings.ReplaceAll(s, "&", "&amp;")
+	s = strings.ReplaceAll(s, "<", "&lt;")
+	s = strings.ReplaceAll(s, ">", "&gt;")
+	return s
+}
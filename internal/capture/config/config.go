@@ -0,0 +1,129 @@
+// Package config loads declarative terminal capture profiles from
+// ~/.do/capture.yml, so `godo capture --profile <name>` reproduces the same
+// capture every time instead of requiring ad-hoc flags on each invocation.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TmuxSelector narrows a tmux capture to a specific session/window/pane.
+// An empty field means "current" for that axis.
+type TmuxSelector struct {
+	Session string `yaml:"session,omitempty"`
+	Window  string `yaml:"window,omitempty"`
+	Pane    string `yaml:"pane,omitempty"`
+}
+
+// ScrollbackRange bounds the captured history, passed through to tmux's
+// `capture-pane -S <start> -E <end>`. Empty fields default to "-" (the
+// start/end of the buffer).
+type ScrollbackRange struct {
+	Start string `yaml:"start,omitempty"`
+	End   string `yaml:"end,omitempty"`
+}
+
+// FilterConfig post-processes captured text before it's written out.
+type FilterConfig struct {
+	// StripANSI removes CSI/OSC escape sequences, leaving plain text.
+	StripANSI bool `yaml:"strip_ansi,omitempty"`
+	// DedupeBlankLines collapses runs of blank lines into one.
+	DedupeBlankLines bool `yaml:"dedupe_blank_lines,omitempty"`
+	// Include, if set, drops any line not matching this regexp.
+	Include string `yaml:"include,omitempty"`
+	// Exclude, if set, drops any line matching this regexp.
+	Exclude string `yaml:"exclude,omitempty"`
+}
+
+// Profile is one named capture pipeline: what to capture, how to clean it
+// up, and where to write it.
+type Profile struct {
+	// Target selects the capturer backend: "auto" (default, same detection
+	// runCapture already does), or an explicit name ("iterm2", "tmux",
+	// "wezterm", "kitty", "alacritty", "windows-terminal", "screen").
+	Target string `yaml:"target,omitempty"`
+	// Tmux narrows which pane a "tmux" target captures; ignored otherwise.
+	Tmux TmuxSelector `yaml:"tmux,omitempty"`
+	// Scrollback bounds how much history is captured.
+	Scrollback ScrollbackRange `yaml:"scrollback,omitempty"`
+	// Filters post-processes the raw capture.
+	Filters FilterConfig `yaml:"filters,omitempty"`
+	// Format selects the output encoding: "raw" (default), "plain",
+	// "html", or "jsonl".
+	Format string `yaml:"format,omitempty"`
+	// Destination is a path template; see RenderDestination for the
+	// supported placeholders.
+	Destination string `yaml:"destination,omitempty"`
+}
+
+// Config is the parsed contents of ~/.do/capture.yml: a named set of
+// profiles selectable via `godo capture --profile <name>`.
+type Config struct {
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// DefaultPath returns ~/.do/capture.yml.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".do", "capture.yml"), nil
+}
+
+// Load reads and parses the capture profile config at path. A missing file
+// is not an error; it yields an empty Config so callers can fall back to
+// flag-driven capture.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{Profiles: map[string]Profile{}}, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]Profile{}
+	}
+	return &cfg, nil
+}
+
+// Profile looks up a named profile, erroring with the list of known names
+// if it isn't defined.
+func (c *Config) Profile(name string) (*Profile, error) {
+	p, ok := c.Profiles[name]
+	if !ok {
+		known := make([]string, 0, len(c.Profiles))
+		for n := range c.Profiles {
+			known = append(known, n)
+		}
+		return nil, fmt.Errorf("no capture profile named %q (known: %s)", name, strings.Join(known, ", "))
+	}
+	return &p, nil
+}
+
+// RenderDestination expands {project} and {ts} placeholders (plus any extra
+// vars the caller supplies) in p.Destination. {ts} defaults to the current
+// time formatted as 20060102-150405 unless vars already provides one.
+func (p *Profile) RenderDestination(vars map[string]string) string {
+	dest := p.Destination
+	merged := map[string]string{"ts": time.Now().Format("20060102-150405")}
+	for k, v := range vars {
+		merged[k] = v
+	}
+	for k, v := range merged {
+		dest = strings.ReplaceAll(dest, "{"+k+"}", v)
+	}
+	return dest
+}
@@ -0,0 +1,12 @@
+//go:build darwin || freebsd || netbsd || openbsd
+
+package tty
+
+import "syscall"
+
+// The BSDs (including macOS) use the TIOCGETA/TIOCSETA ioctl numbers rather
+// than Linux's TCGETS/TCSETS (see termios_linux.go).
+const (
+	ioctlGetTermios = syscall.TIOCGETA
+	ioctlSetTermios = syscall.TIOCSETA
+)
@@ -0,0 +1,12 @@
+//go:build linux
+
+package tty
+
+import "syscall"
+
+// Linux's ioctl request numbers for getting/setting termios differ from the
+// BSDs' (see termios_bsd.go), hence the three-way split.
+const (
+	ioctlGetTermios = syscall.TCGETS
+	ioctlSetTermios = syscall.TCSETS
+)
@@ -0,0 +1,58 @@
+//go:build !windows
+
+package tty
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// getTermios and setTermios wrap the raw TCGETS/TCSETS-family ioctl for the
+// current platform (see termios_linux.go / termios_bsd.go for the request
+// numbers, which differ between Linux and the BSDs).
+func getTermios(fd uintptr) (syscall.Termios, error) {
+	var t syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(ioctlGetTermios), uintptr(unsafe.Pointer(&t)))
+	if errno != 0 {
+		return t, fmt.Errorf("ioctl get termios: %w", errno)
+	}
+	return t, nil
+}
+
+func setTermios(fd uintptr, t syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(ioctlSetTermios), uintptr(unsafe.Pointer(&t)))
+	if errno != 0 {
+		return fmt.Errorf("ioctl set termios: %w", errno)
+	}
+	return nil
+}
+
+// enableRawMode puts fd into raw mode (no echo, no line buffering, no
+// signal generation) so query replies can be read a byte at a time without
+// the terminal driver mangling or withholding them, and returns a restore
+// func that puts the original settings back.
+func enableRawMode(fd uintptr) (func() error, error) {
+	orig, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := orig
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Oflag &^= syscall.OPOST
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 0
+	raw.Cc[syscall.VTIME] = 1
+
+	if err := setTermios(fd, raw); err != nil {
+		return nil, err
+	}
+
+	return func() error {
+		return setTermios(fd, orig)
+	}, nil
+}
@@ -0,0 +1,173 @@
+// Package tty opens /dev/tty directly, following fzf's approach of using
+// a separate terminal handle rather than inheriting stdio, so terminal
+// queries still work when godo's own stdout is redirected to a pipe or
+// file (which otherwise makes every TERM_PROGRAM/os.Stdout-based detector
+// fall through to Unknown) and as a fallback when AppleScript automation
+// is denied or TERM_PROGRAM is empty (both common over SSH).
+//
+// It can only use the escape-sequence query vocabulary every terminal
+// emulator exposes (DECRQCRA, CSI 18 t, DSR) — none of which return the
+// visible screen's actual text. That capability isn't part of the
+// terminal query protocol at all; DECRQCRA itself only ever returns a
+// checksum. What this package can reliably report is window size, cursor
+// position, and whether the terminal answers queries at all, which
+// Snapshot folds into a short textual report used as a last-resort
+// fallback when no richer Capturer (tmux, a terminal-specific backend) is
+// available.
+package tty
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// queryTimeout bounds how long we wait for a terminal to answer a query
+// sequence before giving up on it.
+const queryTimeout = 200 * time.Millisecond
+
+var (
+	windowSizeRe = regexp.MustCompile(`\x1b\[8;(\d+);(\d+)t`)
+	cursorPosRe  = regexp.MustCompile(`\x1b\[(\d+);(\d+)R`)
+	decrqcraRe   = regexp.MustCompile(`\x1bP\d*!~[0-9A-Fa-f]*\x1b\\`)
+)
+
+// TTY is a direct, independent handle on /dev/tty.
+type TTY struct {
+	f       *os.File
+	restore func() error
+}
+
+// Open opens /dev/tty read-write and puts it into raw mode so query
+// replies can be read byte-by-byte without echoing to the screen or
+// waiting for a newline. Callers must call Close to restore the original
+// terminal settings.
+func Open() (*TTY, error) {
+	f, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("open /dev/tty: %w", err)
+	}
+	restore, err := enableRawMode(f.Fd())
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("set /dev/tty raw mode: %w", err)
+	}
+	return &TTY{f: f, restore: restore}, nil
+}
+
+// Close restores the original termios settings and closes the handle.
+func (t *TTY) Close() error {
+	if t.restore != nil {
+		_ = t.restore()
+	}
+	return t.f.Close()
+}
+
+// query writes request to the tty and reads back whatever arrives within
+// queryTimeout, returning early once the response matches terminator (or
+// reading until the deadline if terminator is nil).
+func (t *TTY) query(request string, terminator *regexp.Regexp) (string, error) {
+	if _, err := t.f.WriteString(request); err != nil {
+		return "", fmt.Errorf("write query: %w", err)
+	}
+
+	deadline := time.Now().Add(queryTimeout)
+	_ = t.f.SetReadDeadline(deadline)
+
+	r := bufio.NewReader(t.f)
+	var resp []byte
+	buf := make([]byte, 64)
+	for time.Now().Before(deadline) {
+		n, err := r.Read(buf)
+		if n > 0 {
+			resp = append(resp, buf[:n]...)
+			if terminator != nil && terminator.Match(resp) {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	return string(resp), nil
+}
+
+// Size queries the terminal's size in character cells via CSI 18 t
+// ("report window size in characters"). This works over /dev/tty even
+// when fd 1 (stdout) isn't a tty at all.
+func (t *TTY) Size() (rows, cols int, err error) {
+	resp, err := t.query("\x1b[18t", windowSizeRe)
+	if err != nil {
+		return 0, 0, err
+	}
+	m := windowSizeRe.FindStringSubmatch(resp)
+	if m == nil {
+		return 0, 0, fmt.Errorf("terminal did not respond to CSI 18 t")
+	}
+	rows, _ = strconv.Atoi(m[1])
+	cols, _ = strconv.Atoi(m[2])
+	return rows, cols, nil
+}
+
+// CursorPosition queries the cursor's current row/column via DSR (CSI 6n).
+func (t *TTY) CursorPosition() (row, col int, err error) {
+	resp, err := t.query("\x1b[6n", cursorPosRe)
+	if err != nil {
+		return 0, 0, err
+	}
+	m := cursorPosRe.FindStringSubmatch(resp)
+	if m == nil {
+		return 0, 0, fmt.Errorf("terminal did not respond to DSR (CSI 6n)")
+	}
+	row, _ = strconv.Atoi(m[1])
+	col, _ = strconv.Atoi(m[2])
+	return row, col, nil
+}
+
+// SupportsDECRQCRA reports whether the terminal answers a DECRQCRA
+// (request checksum of rectangular area) query at all. DECRQCRA only
+// ever returns a checksum of the requested area, never its text, so this
+// is purely a capability probe — useful for deciding whether a terminal
+// implements the fuller VT420 query set — not a way to read content.
+func (t *TTY) SupportsDECRQCRA() bool {
+	resp, err := t.query("\x1bP1!~1;1;1;1;1*y", decrqcraRe)
+	if err != nil {
+		return false
+	}
+	return decrqcraRe.MatchString(resp)
+}
+
+// Snapshot captures what's actually queryable about the terminal state.
+type Snapshot struct {
+	Rows, Cols       int
+	CursorRow        int
+	CursorCol        int
+	SupportsDECRQCRA bool
+}
+
+// TakeSnapshot gathers a Snapshot, tolerating individual query failures —
+// a terminal that ignores one query might still answer another.
+func (t *TTY) TakeSnapshot() Snapshot {
+	var snap Snapshot
+	if rows, cols, err := t.Size(); err == nil {
+		snap.Rows, snap.Cols = rows, cols
+	}
+	if row, col, err := t.CursorPosition(); err == nil {
+		snap.CursorRow, snap.CursorCol = row, col
+	}
+	snap.SupportsDECRQCRA = t.SupportsDECRQCRA()
+	return snap
+}
+
+// Report renders Snapshot as the short textual fallback capture. This is
+// not a substitute for real scrollback — just what can honestly be known
+// about the terminal when no richer Capturer is available.
+func (s Snapshot) Report() string {
+	return fmt.Sprintf(
+		"(tty query fallback — no scrollback available via escape-sequence queries)\nsize: %dx%d\ncursor: row %d, col %d\nDECRQCRA supported: %v\n",
+		s.Cols, s.Rows, s.CursorRow, s.CursorCol, s.SupportsDECRQCRA,
+	)
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package tty
+
+import "fmt"
+
+// enableRawMode is not implemented on Windows: there's no /dev/tty to open
+// in the first place (Open's os.OpenFile("/dev/tty", ...) call fails before
+// this would ever run), and Windows consoles use a separate mode-flag API
+// (SetConsoleMode) rather than termios. This stub exists only so the
+// package still builds on Windows.
+func enableRawMode(fd uintptr) (func() error, error) {
+	return nil, fmt.Errorf("tty: raw mode is not supported on windows")
+}
@@ -0,0 +1,138 @@
+package shellintegration
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// bashZshSnippet is the POSIX-shell wrapper shared by bash and zsh: it
+// shadows `claude` with a function that tees output into a per-session log
+// under <project>/.do/claude-session/YYYY/MM/DD/.
+const bashZshSnippet = `if command -v claude &> /dev/null; then
+    claude_original="$(which claude)"
+    claude() {
+        local git_root=$(git rev-parse --show-toplevel 2>/dev/null || echo "$PWD")
+        local session_date=$(date +%Y/%m/%d)
+        local session_dir="${git_root}/.do/claude-session/${session_date}"
+        mkdir -p "$session_dir"
+        export CLAUDE_SESSION_ID=$(date +%Y%m%d-%H%M%S)
+        local log_file=${session_dir}/${CLAUDE_SESSION_ID}.session
+        echo "Session: $CLAUDE_SESSION_ID" >&2
+        "$claude_original" "$@" 2>&1 | tee -a "$log_file"
+        return ${PIPESTATUS[0]}
+    }
+fi`
+
+// BashDriver installs the wrapper into ~/.bashrc.
+func BashDriver() *Driver {
+	return &Driver{
+		ShellName: "bash",
+		ProfilePath: func() (string, error) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(home, ".bashrc"), nil
+		},
+		Snippet: func() string { return bashZshSnippet },
+	}
+}
+
+// ZshDriver installs the wrapper into ~/.zshrc.
+func ZshDriver() *Driver {
+	return &Driver{
+		ShellName: "zsh",
+		ProfilePath: func() (string, error) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(home, ".zshrc"), nil
+		},
+		Snippet: func() string { return bashZshSnippet },
+	}
+}
+
+const fishSnippet = `if command -v claude >/dev/null
+    function claude
+        set -l git_root (git rev-parse --show-toplevel 2>/dev/null; or echo $PWD)
+        set -l session_dir $git_root/.do/claude-session/(date +%Y/%m/%d)
+        mkdir -p $session_dir
+        set -gx CLAUDE_SESSION_ID (date +%Y%m%d-%H%M%S)
+        set -l log_file $session_dir/$CLAUDE_SESSION_ID.session
+        echo "Session: $CLAUDE_SESSION_ID" >&2
+        command claude $argv 2>&1 | tee -a $log_file
+    end
+end`
+
+// FishDriver installs the wrapper into ~/.config/fish/config.fish.
+func FishDriver() *Driver {
+	return &Driver{
+		ShellName: "fish",
+		ProfilePath: func() (string, error) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(home, ".config", "fish", "config.fish"), nil
+		},
+		Snippet: func() string { return fishSnippet },
+	}
+}
+
+const powerShellSnippet = `if (Get-Command claude -ErrorAction SilentlyContinue) {
+    function claude {
+        $gitRoot = (git rev-parse --show-toplevel 2>$null)
+        if (-not $gitRoot) { $gitRoot = (Get-Location).Path }
+        $sessionDir = Join-Path $gitRoot (".do/claude-session/" + (Get-Date -Format "yyyy/MM/dd"))
+        New-Item -ItemType Directory -Force -Path $sessionDir | Out-Null
+        $env:CLAUDE_SESSION_ID = Get-Date -Format "yyyyMMdd-HHmmss"
+        $logFile = Join-Path $sessionDir ($env:CLAUDE_SESSION_ID + ".session")
+        Write-Host "Session: $($env:CLAUDE_SESSION_ID)"
+        & claude.exe @args 2>&1 | Tee-Object -FilePath $logFile -Append
+    }
+}`
+
+// PowerShellDriver installs the wrapper into $PROFILE.
+func PowerShellDriver() *Driver {
+	return &Driver{
+		ShellName: "powershell",
+		ProfilePath: func() (string, error) {
+			if p := os.Getenv("PROFILE"); p != "" {
+				return p, nil
+			}
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(home, "Documents", "PowerShell", "Microsoft.PowerShell_profile.ps1"), nil
+		},
+		Snippet: func() string { return powerShellSnippet },
+	}
+}
+
+const nushellSnippet = `def --env claude [...args] {
+    let git_root = (do { git rev-parse --show-toplevel } | complete | get stdout | str trim)
+    let root = if ($git_root | is-empty) { $env.PWD } else { $git_root }
+    let session_dir = ($root | path join ".do" "claude-session" (date now | format date "%Y/%m/%d"))
+    mkdir $session_dir
+    $env.CLAUDE_SESSION_ID = (date now | format date "%Y%m%d-%H%M%S")
+    let log_file = ($session_dir | path join ($env.CLAUDE_SESSION_ID + ".session"))
+    print $"Session: ($env.CLAUDE_SESSION_ID)"
+    ^claude ...$args | tee { save --append $log_file }
+}`
+
+// NushellDriver installs the wrapper into ~/.config/nushell/config.nu.
+func NushellDriver() *Driver {
+	return &Driver{
+		ShellName: "nushell",
+		ProfilePath: func() (string, error) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			return filepath.Join(home, ".config", "nushell", "config.nu"), nil
+		},
+		Snippet: func() string { return nushellSnippet },
+	}
+}
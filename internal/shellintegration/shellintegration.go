@@ -0,0 +1,177 @@
+// Package shellintegration installs and removes the "godo" Claude-session
+// logging wrapper from a user's shell startup files, across shells.
+package shellintegration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Markers fence the block godo manages inside a shell profile, so installs
+// are idempotent and uninstalls only remove what godo added.
+const (
+	beginMarker = "# >>> do-focus >>>"
+	endMarker   = "# <<< do-focus <<<"
+)
+
+// Driver installs/uninstalls the claude logging wrapper for one shell.
+type Driver struct {
+	// ShellName identifies the shell (bash, zsh, fish, powershell, nushell).
+	ShellName string
+	// ProfilePath returns the path to the shell's startup file.
+	ProfilePath func() (string, error)
+	// Snippet returns the shell-specific wrapper function body.
+	Snippet func() string
+}
+
+// Drivers returns all supported shell drivers in detection priority order.
+func Drivers() []*Driver {
+	return []*Driver{
+		BashDriver(),
+		ZshDriver(),
+		FishDriver(),
+		PowerShellDriver(),
+		NushellDriver(),
+	}
+}
+
+// Detect returns the driver matching the user's $SHELL, or nil if unknown.
+// PowerShell and nushell aren't detectable via $SHELL, so callers needing
+// those should select them explicitly by ShellName.
+func Detect(shellEnv string) *Driver {
+	base := filepath.Base(shellEnv)
+	for _, d := range Drivers() {
+		if strings.Contains(base, d.ShellName) {
+			return d
+		}
+	}
+	return nil
+}
+
+// ByName returns the driver with the given ShellName, or nil.
+func ByName(name string) *Driver {
+	for _, d := range Drivers() {
+		if d.ShellName == name {
+			return d
+		}
+	}
+	return nil
+}
+
+func block(snippet string) string {
+	return beginMarker + "\n" + snippet + "\n" + endMarker + "\n"
+}
+
+func markerSpan(content string) (start, end int, ok bool) {
+	start = strings.Index(content, beginMarker)
+	if start == -1 {
+		return 0, 0, false
+	}
+	endMarkerIdx := strings.Index(content[start:], endMarker)
+	if endMarkerIdx == -1 {
+		return 0, 0, false
+	}
+	end = start + endMarkerIdx + len(endMarker)
+	// Consume a single trailing newline so re-installs don't accumulate blank lines.
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return start, end, true
+}
+
+// IsInstalled reports whether the fenced godo block is present in the
+// driver's profile file.
+func (d *Driver) IsInstalled() (bool, error) {
+	path, err := d.ProfilePath()
+	if err != nil {
+		return false, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	_, _, ok := markerSpan(string(content))
+	return ok, nil
+}
+
+// Install appends the fenced wrapper block to the profile file, unless
+// already installed. When dryRun is true, no file is modified and the diff
+// that would have been written is returned instead.
+func (d *Driver) Install(dryRun bool) (diff string, err error) {
+	path, err := d.ProfilePath()
+	if err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+
+	if _, _, ok := markerSpan(string(content)); ok {
+		return "", nil // already installed
+	}
+
+	addition := "\n" + block(d.Snippet())
+	if dryRun {
+		return "+" + strings.ReplaceAll(strings.TrimRight(addition, "\n"), "\n", "\n+") + "\n", nil
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("create %s: %w", dir, err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(addition); err != nil {
+		return "", fmt.Errorf("write %s: %w", path, err)
+	}
+
+	return addition, nil
+}
+
+// Uninstall removes the fenced godo block from the profile file, leaving
+// the rest of the file untouched. When dryRun is true, no file is modified.
+func (d *Driver) Uninstall(dryRun bool) (diff string, err error) {
+	path, err := d.ProfilePath()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil // nothing to remove
+		}
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+	content := string(data)
+
+	start, end, ok := markerSpan(content)
+	if !ok {
+		return "", nil // not installed
+	}
+	removed := content[start:end]
+
+	if dryRun {
+		return "-" + strings.ReplaceAll(strings.TrimRight(removed, "\n"), "\n", "\n-") + "\n", nil
+	}
+
+	newContent := content[:start] + content[end:]
+	if err := os.WriteFile(path, []byte(newContent), 0644); err != nil {
+		return "", fmt.Errorf("write %s: %w", path, err)
+	}
+
+	return removed, nil
+}